@@ -6,6 +6,11 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
 )
 
 // A function taking a Slack teamID (workspace ID) that returns
@@ -21,30 +26,122 @@ type Config struct {
 	Router *http.ServeMux
 	// Optional. Adds a path to the start of the Slack routes.
 	PathPrefix string
-	// Required. Method for fetching bot tokens
-	// for a workspace based on its team ID
+	// Optional. Serves slash commands, interactions and events from
+	// a single route, e.g. "/slack", instead of the separate routes.
+	//
+	// Slack allows one Request URL to be used for all of them.
+	SingleEndpoint string
+	// Required unless BotTokenResolver or Installations is set.
+	// Method for fetching bot tokens for a workspace based on its team ID
 	BotToken BotTokenGetter
+	// Optional. Method for fetching bot tokens based on the enterprise,
+	// team and authorizations of a request.
+	//
+	// Takes precedence over BotToken.
+	BotTokenResolver BotTokenResolver
+	// Optional. Method for fetching the token of the user who triggered
+	// a request, used by each request's UserClient.
+	//
+	// Defaults to reading user tokens from Installations.
+	UserToken UserTokenResolver
+	// Optional. A store of Slack App installations.
+	//
+	// When BotToken is nil, bot tokens are read from this store.
+	// If OAuth is also set, expiring tokens are refreshed and saved
+	// before handlers receive their Client.
+	Installations InstallationStore
+	// Optional. Enables the OAuth v2 installation flow, which saves
+	// installations to Installations.
+	OAuth *OAuthConfig
 	// Required. The Slack webhook signing secret for your app
 	SigningSecret string
+	// Optional. The Slack Web API base URL used by every Slack API
+	// client and OAuth call Slap makes. Useful for testing against
+	// a local server.
+	//
+	// Defaults to: "https://slack.com/api/".
+	APIURL string
+	// Optional. The HTTP client used for Slack API calls.
+	//
+	// Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Optional. Options applied to every Slack API client Slap creates.
+	//
+	// Use HTTPClient to set the HTTP client, as slack.OptionHTTPClient
+	// is overridden.
+	ClientOptions []slack.Option
+	// Optional. How long a workspace's bot client is cached for.
+	// Clients are evicted early if Slack rejects their token.
+	//
+	// Defaults to 5 minutes. Set a negative value to disable caching.
+	ClientCacheTTL time.Duration
+	// Optional. Records verified requests and their responses
+	// for replaying with the replay package.
+	Recorder *Recorder
 	// A logger for the Slap Application
 	Logger *slog.Logger
 	// A generic, ephemeral error message to send the user
-	// when a handler returns an error.
+	// when a handler returns an error. With a Catalog, this is
+	// also the message's key.
 	//
 	// Defaults to: "An error occurred".
 	ErrorMessage string
+	// Optional. Tells the user that a handler returned an error.
+	//
+	// Defaults to DefaultErrorHandler(ErrorMessage).
+	ErrorHandler ErrorHandler
+	// Optional. The secret used to sign private_metadata created with
+	// EncodeMetadata.
+	//
+	// Defaults to a key derived from SigningSecret.
+	MetadataSecret string
+	// Optional. Keeps values too large for a view's private_metadata,
+	// which then only holds their key. Without a store, EncodeMetadata
+	// returns ErrMetadataTooLarge for them.
+	MetadataStore MetadataStore
+	// Optional. How response actions are checked against Block Kit
+	// limits before they are sent to Slack.
+	//
+	// Defaults to WarnBlockLimits. Use EnforceBlockLimits in development.
+	BlockLimits BlockLimitMode
+	// Optional. Translated messages for Request.T and the error
+	// messages of DefaultErrorHandler.
+	Catalog *Catalog
+	// Optional. How long a user's locale from users.info is cached for.
+	//
+	// Defaults to 1 hour. Set a negative value to disable caching.
+	LocaleCacheTTL time.Duration
 }
 
 // A Slap Application.
 type Application struct {
 	signingSecret   string
-	botToken        BotTokenGetter
-	errorMessage    string
+	botToken        BotTokenResolver
+	userToken       UserTokenResolver
+	installations   InstallationStore
+	oauth           *OAuthConfig
+	errorHandler    ErrorHandler
+	metadataSecret  string
+	metadataStore   MetadataStore
+	blockLimits     BlockLimitMode
+	catalog         *Catalog
+	locales         *localeCache
 	commands        map[string]CommandHandler
 	blockActions    map[string]BlockActionHandler
 	viewSubmissions map[string]ViewSubmissionHandler
 	events          map[string]EventHandler
+	shortcuts       map[string]MessageShortcutHandler
+	viewValidators  map[string]Validator
+	viewClosed      map[string]ViewClosedHandler
 	logger          *slog.Logger
+	apiURL          string
+	httpClient      *http.Client
+	clientOptions   []slack.Option
+	clients         *clientCache
+	recorder        *Recorder
+	rotationsMu     sync.Mutex
+	rotations       map[string]*rotation
+	oauthStates     *usedOAuthStates
 }
 
 // Registers a slash command handler.
@@ -104,10 +201,28 @@ func New(config Config) *Application {
 		panic("Missing Slack signing secret")
 	}
 
-	if config.BotToken == nil {
+	if config.BotToken == nil && config.BotTokenResolver == nil && config.Installations == nil {
 		panic("Missing Slack bot token getter")
 	}
 
+	apiURL := config.APIURL
+	if apiURL == "" {
+		apiURL = slack.APIURL
+	}
+	if !strings.HasSuffix(apiURL, "/") {
+		apiURL += "/"
+	}
+
+	var oauth *OAuthConfig
+	if config.OAuth != nil {
+		if config.Installations == nil {
+			panic("Missing installation store for OAuth")
+		}
+		oauthConfig := *config.OAuth
+		oauthConfig.setDefaults(config.SigningSecret, apiURL)
+		oauth = &oauthConfig
+	}
+
 	logger := config.Logger
 	if logger == nil {
 		logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
@@ -120,20 +235,86 @@ func New(config Config) *Application {
 		errorMessage = "An error occurred"
 	}
 
+	errorHandler := config.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = DefaultErrorHandler(errorMessage)
+	}
+
+	metadataSecret := config.MetadataSecret
+	if metadataSecret == "" {
+		metadataSecret = deriveSecret(config.SigningSecret, "slap-metadata")
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	clientCacheTTL := config.ClientCacheTTL
+	if clientCacheTTL == 0 {
+		clientCacheTTL = defaultClientCacheTTL
+	}
+
+	localeCacheTTL := config.LocaleCacheTTL
+	if localeCacheTTL == 0 {
+		localeCacheTTL = defaultLocaleCacheTTL
+	}
+
 	app := Application{
 		logger:          logger,
-		botToken:        config.BotToken,
+		botToken:        config.BotTokenResolver,
+		userToken:       config.UserToken,
+		installations:   config.Installations,
+		oauth:           oauth,
 		signingSecret:   config.SigningSecret,
-		errorMessage:    errorMessage,
+		errorHandler:    errorHandler,
+		metadataSecret:  metadataSecret,
+		metadataStore:   config.MetadataStore,
+		blockLimits:     config.BlockLimits,
+		catalog:         config.Catalog,
+		locales:         newLocaleCache(localeCacheTTL),
 		commands:        make(map[string]CommandHandler),
 		blockActions:    make(map[string]BlockActionHandler),
 		viewSubmissions: make(map[string]ViewSubmissionHandler),
 		events:          make(map[string]EventHandler),
+		shortcuts:       make(map[string]MessageShortcutHandler),
+		viewValidators:  make(map[string]Validator),
+		viewClosed:      make(map[string]ViewClosedHandler),
+		rotations:       make(map[string]*rotation),
+		oauthStates:     newUsedOAuthStates(),
+		apiURL:          apiURL,
+		httpClient:      httpClient,
+		clientOptions:   config.ClientOptions,
+		clients:         newClientCache(clientCacheTTL),
+		recorder:        config.Recorder,
+	}
+
+	if app.botToken == nil && config.BotToken != nil {
+		getter := config.BotToken
+		app.botToken = func(ctx AuthorizeContext) (string, error) {
+			return getter(ctx.TeamID)
+		}
+	}
+	if app.botToken == nil {
+		app.botToken = app.installationBotToken
+	}
+	if app.userToken == nil && app.installations != nil {
+		app.userToken = app.installationUserToken
+	}
+
+	if app.oauth != nil {
+		config.Router.HandleFunc(fmt.Sprintf("GET %v/install", config.PathPrefix), app.handleInstall)
+		config.Router.HandleFunc(fmt.Sprintf("GET %v/oauth_redirect", config.PathPrefix), app.handleOAuthRedirect)
+	}
+
+	if config.SingleEndpoint != "" {
+		config.Router.HandleFunc(fmt.Sprintf("POST %v%v", config.PathPrefix, config.SingleEndpoint), app.validateSignature(app.record(app.handleSingleEndpoint)))
+		return &app
 	}
 
-	config.Router.HandleFunc(fmt.Sprintf("POST %v/commands", config.PathPrefix), app.validateSignature(app.handleCommand))
-	config.Router.HandleFunc(fmt.Sprintf("POST %v/interactions", config.PathPrefix), app.validateSignature(app.handleInteraction))
-	config.Router.HandleFunc(fmt.Sprintf("POST %v/events", config.PathPrefix), app.validateSignature(app.handleEvent))
+	config.Router.HandleFunc(fmt.Sprintf("POST %v/commands", config.PathPrefix), app.validateSignature(app.record(app.handleCommand)))
+	config.Router.HandleFunc(fmt.Sprintf("POST %v/interactions", config.PathPrefix), app.validateSignature(app.record(app.handleInteraction)))
+	config.Router.HandleFunc(fmt.Sprintf("POST %v/events", config.PathPrefix), app.validateSignature(app.record(app.handleEvent)))
 
 	return &app
 }
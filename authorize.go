@@ -0,0 +1,62 @@
+package slap
+
+// An app installation that an Events API event was delivered for.
+type Authorization struct {
+	EnterpriseID        string `json:"enterprise_id"`
+	TeamID              string `json:"team_id"`
+	UserID              string `json:"user_id"`
+	IsBot               bool   `json:"is_bot"`
+	IsEnterpriseInstall bool   `json:"is_enterprise_install"`
+}
+
+// Identifies the installation a request from Slack belongs to.
+type AuthorizeContext struct {
+	// The Enterprise ID if the workspace belongs to an Enterprise Grid.
+	EnterpriseID string
+	// The Team ID of the workspace.
+	TeamID string
+	// Whether the app was installed to the entire Enterprise Grid.
+	IsEnterpriseInstall bool
+	// The ID of the user who triggered the request, if any.
+	UserID string
+	// The installations an Events API event was delivered for.
+	//
+	// Empty for slash commands and interactions.
+	Authorizations []Authorization
+}
+
+// A function that returns the bot token for the installation
+// a request belongs to.
+//
+// Use this instead of a BotTokenGetter for Enterprise Grid
+// org-wide installs and Slack Connect channels.
+type BotTokenResolver func(ctx AuthorizeContext) (string, error)
+
+// Creates the AuthorizeContext for an Events API event.
+//
+// In Slack Connect channels the event's team ID may belong to
+// another organisation, so the event's first authorization is
+// used to identify our installation instead.
+func eventAuthorizeContext(o baseOuterEvent, userID string) AuthorizeContext {
+	ctx := AuthorizeContext{
+		EnterpriseID:   o.EnterpriseID,
+		TeamID:         o.TeamID,
+		UserID:         userID,
+		Authorizations: o.Authorizations,
+	}
+	if len(o.Authorizations) > 0 {
+		auth := o.Authorizations[0]
+		ctx.EnterpriseID = auth.EnterpriseID
+		ctx.TeamID = auth.TeamID
+		ctx.IsEnterpriseInstall = auth.IsEnterpriseInstall
+	}
+	return ctx
+}
+
+func (ctx AuthorizeContext) installationQuery() InstallationQuery {
+	return InstallationQuery{
+		EnterpriseID:        ctx.EnterpriseID,
+		TeamID:              ctx.TeamID,
+		IsEnterpriseInstall: ctx.IsEnterpriseInstall,
+	}
+}
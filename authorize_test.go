@@ -0,0 +1,100 @@
+package slap_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+)
+
+func TestAuthorizeEventUsesAuthorizations(t *testing.T) {
+	t.Parallel()
+
+	contexts := make(chan slap.AuthorizeContext, 1)
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotTokenResolver: func(ctx slap.AuthorizeContext) (string, error) {
+			contexts <- ctx
+			return "test", nil
+		},
+	})
+	app.RegisterEventHandler("message", func(req *slap.EventRequest) error {
+		req.Ack()
+		req.Client()
+		return nil
+	})
+
+	payload, err := getJSONTestData("event_message.json")
+	if err != nil {
+		t.Errorf("Could not get testdata: %v", err.Error())
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(payload))
+	r.Header.Add("content-type", "application/json")
+	addSignatureHeaders(r)
+
+	router.ServeHTTP(w, r)
+
+	ctx := <-contexts
+	teamGot, teamWant := ctx.TeamID, "T123ABC456"
+	if teamGot != teamWant {
+		t.Errorf("Unexpected team ID, got: %v, want: %v", teamGot, teamWant)
+	}
+
+	enterpriseGot, enterpriseWant := ctx.EnterpriseID, "E123ABC456"
+	if enterpriseGot != enterpriseWant {
+		t.Errorf("Unexpected enterprise ID, got: %v, want: %v", enterpriseGot, enterpriseWant)
+	}
+
+	userGot, userWant := ctx.UserID, "U123ABC456"
+	if userGot != userWant {
+		t.Errorf("Unexpected user ID, got: %v, want: %v", userGot, userWant)
+	}
+}
+
+func TestAuthorizeOrgWideInstall(t *testing.T) {
+	t.Parallel()
+
+	store := slap.NewMemoryInstallationStore()
+	store.Save(slap.Installation{
+		EnterpriseID:        "E0123456",
+		IsEnterpriseInstall: true,
+		BotToken:            "xoxb-org",
+	})
+
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		Installations: store,
+	})
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.Ack()
+		return nil
+	})
+
+	body, err := url.ParseQuery(string(testCommandBody()))
+	if err != nil {
+		t.Fatalf("Could not parse command body: %v", err.Error())
+	}
+	body.Set("enterprise_id", "E0123456")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/commands", bytes.NewReader([]byte(body.Encode())))
+	r.Header.Add("content-type", "application/x-www-form-urlencoded")
+	addSignatureHeaders(r)
+
+	router.ServeHTTP(w, r)
+	res := w.Result()
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+}
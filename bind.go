@@ -0,0 +1,358 @@
+package slap
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	optionType  = reflect.TypeOf(slack.OptionBlockObject{})
+	optionsType = reflect.TypeOf([]slack.OptionBlockObject{})
+	addressType = reflect.TypeOf(mail.Address{})
+	urlType     = reflect.TypeOf(url.URL{})
+)
+
+// A value that could not be converted to its field's type
+type bindValueError struct {
+	message string
+}
+
+func (e *bindValueError) Error() string {
+	return e.message
+}
+
+// Binds the state of the submitted view into the fields of the
+// struct pointed to by v. See BindState.
+func (req *ViewSubmissionRequest) Bind(v any) error {
+	if req.Payload.View.State == nil {
+		return BindState(nil, v)
+	}
+	return BindState(req.Payload.View.State.Values, v)
+}
+
+// Binds the state of the message or view containing the action into
+// the fields of the struct pointed to by v. See BindState.
+func (req *BlockActionRequest) Bind(v any) error {
+	if req.Payload.State != nil {
+		return BindState(req.Payload.State.Values, v)
+	}
+	if req.Payload.View != nil && req.Payload.View.State != nil {
+		return BindState(req.Payload.View.State.Values, v)
+	}
+	return BindState(nil, v)
+}
+
+// Binds the values of input blocks into the fields of the struct
+// pointed to by v, using "slap" tags of the form "blockID/actionID":
+//
+//	type Form struct {
+//		Name     string    `slap:"name-block/name-input"`
+//		Age      int       `slap:"age-block/age-input"`
+//		Reviewer string    `slap:"reviewer-block/user-select"`
+//		Tags     []string  `slap:"tags-block/tags-select"`
+//		Due      time.Time `slap:"due-block/due-datepicker"`
+//	}
+//
+// Fields are converted from the element's value based on its type:
+//
+//   - string: the input's value, the selected option's value, user,
+//     conversation or channel ID, date ("2006-01-02") or time ("15:04")
+//   - []string: the selected options' values, users, conversations
+//     or channels
+//   - int, uint and float types: a number input's value
+//   - bool: whether any checkbox is checked
+//   - time.Time: a date (in UTC), time or datetime picker's value
+//   - slack.OptionBlockObject and []slack.OptionBlockObject: the
+//     selected options
+//   - mail.Address: an email input's value
+//   - url.URL: a URL input's value
+//
+// Pointer fields are left nil when the element has no value, and
+// fields for elements that aren't in the state are left unchanged.
+//
+// Fields are then validated with their "validate" tags, e.g.
+// `validate:"required,min=3,max=80"`. See FormHandler.
+//
+// Returns FieldErrors for values that cannot be converted or are
+// invalid, which are shown below their input blocks if returned by
+// a handler.
+func BindState(state map[string]map[string]slack.BlockAction, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("Bind requires a pointer to a struct")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	fields := make(map[string]string)
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		blockID, actionID, ok, err := parseBindTag(field)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		action, ok := state[blockID][actionID]
+		if !ok {
+			continue
+		}
+
+		err = setField(rv.Field(i), action)
+		var valueErr *bindValueError
+		if errors.As(err, &valueErr) {
+			fields[blockID] = valueErr.message
+		} else if err != nil {
+			return fmt.Errorf("Could not bind %v: %w", field.Name, err)
+		}
+	}
+
+	invalid, err := validateStruct(rv, state)
+	if err != nil {
+		return err
+	}
+	for blockID, message := range invalid {
+		if _, ok := fields[blockID]; !ok {
+			fields[blockID] = message
+		}
+	}
+
+	if len(fields) > 0 {
+		return FieldErrors(fields)
+	}
+	return nil
+}
+
+// Reads the block and action IDs from a field's "slap" tag.
+func parseBindTag(field reflect.StructField) (string, string, bool, error) {
+	tag, _, _ := strings.Cut(field.Tag.Get("slap"), ",")
+	if tag == "" || tag == "-" || !field.IsExported() {
+		return "", "", false, nil
+	}
+	blockID, actionID, ok := strings.Cut(tag, "/")
+	if !ok || blockID == "" || actionID == "" {
+		return "", "", false, fmt.Errorf("Invalid slap tag %q on %v, expected \"blockID/actionID\"", tag, field.Name)
+	}
+	return blockID, actionID, true, nil
+}
+
+func setField(field reflect.Value, action slack.BlockAction) error {
+	if field.Kind() == reflect.Pointer {
+		if !hasValue(action) {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		value := reflect.New(field.Type().Elem())
+		if err := setField(value.Elem(), action); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+
+	switch field.Type() {
+	case timeType:
+		t, err := timeValue(action)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	case optionType:
+		field.Set(reflect.ValueOf(action.SelectedOption))
+		return nil
+	case optionsType:
+		field.Set(reflect.ValueOf(action.SelectedOptions))
+		return nil
+	case addressType:
+		value := stringValue(action)
+		if value == "" {
+			return nil
+		}
+		address, err := mail.ParseAddress(value)
+		if err != nil {
+			return &bindValueError{"Enter a valid email address."}
+		}
+		field.Set(reflect.ValueOf(*address))
+		return nil
+	case urlType:
+		value := stringValue(action)
+		if value == "" {
+			return nil
+		}
+		u, err := url.Parse(value)
+		if err != nil {
+			return &bindValueError{"Enter a valid URL."}
+		}
+		field.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(stringValue(action))
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("Unsupported field type %v", field.Type())
+		}
+		values := stringsValue(action)
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			slice.Index(i).SetString(value)
+		}
+		field.Set(slice)
+	case reflect.Bool:
+		if string(action.Type) == "checkboxes" {
+			field.SetBool(len(action.SelectedOptions) > 0)
+			return nil
+		}
+		value := stringValue(action)
+		if value == "" {
+			field.SetBool(false)
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return &bindValueError{"Enter true or false."}
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value := stringValue(action)
+		if value == "" {
+			field.SetInt(0)
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return &bindValueError{"Enter a whole number."}
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value := stringValue(action)
+		if value == "" {
+			field.SetUint(0)
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return &bindValueError{"Enter a positive whole number."}
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		value := stringValue(action)
+		if value == "" {
+			field.SetFloat(0)
+			return nil
+		}
+		n, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return &bindValueError{"Enter a number."}
+		}
+		field.SetFloat(n)
+	default:
+		return fmt.Errorf("Unsupported field type %v", field.Type())
+	}
+	return nil
+}
+
+// The single value of an element.
+func stringValue(action slack.BlockAction) string {
+	switch string(action.Type) {
+	case "static_select", "external_select", "radio_buttons", "overflow":
+		return action.SelectedOption.Value
+	case "users_select":
+		return action.SelectedUser
+	case "conversations_select":
+		return action.SelectedConversation
+	case "channels_select":
+		return action.SelectedChannel
+	case "datepicker":
+		return action.SelectedDate
+	case "timepicker":
+		return action.SelectedTime
+	case "datetimepicker":
+		if action.SelectedDateTime == 0 {
+			return ""
+		}
+		return strconv.FormatInt(action.SelectedDateTime, 10)
+	default:
+		return action.Value
+	}
+}
+
+// The values of a multi-select or checkboxes element.
+func stringsValue(action slack.BlockAction) []string {
+	switch string(action.Type) {
+	case "multi_static_select", "multi_external_select", "checkboxes":
+		values := make([]string, len(action.SelectedOptions))
+		for i, option := range action.SelectedOptions {
+			values[i] = option.Value
+		}
+		return values
+	case "multi_users_select":
+		return action.SelectedUsers
+	case "multi_conversations_select":
+		return action.SelectedConversations
+	case "multi_channels_select":
+		return action.SelectedChannels
+	}
+	if value := stringValue(action); value != "" {
+		return []string{value}
+	}
+	return []string{}
+}
+
+func timeValue(action slack.BlockAction) (time.Time, error) {
+	switch string(action.Type) {
+	case "datepicker":
+		if action.SelectedDate == "" {
+			return time.Time{}, nil
+		}
+		t, err := time.Parse(time.DateOnly, action.SelectedDate)
+		if err != nil {
+			return time.Time{}, &bindValueError{"Enter a valid date."}
+		}
+		return t, nil
+	case "timepicker":
+		if action.SelectedTime == "" {
+			return time.Time{}, nil
+		}
+		t, err := time.Parse("15:04", action.SelectedTime)
+		if err != nil {
+			return time.Time{}, &bindValueError{"Enter a valid time."}
+		}
+		return t, nil
+	case "datetimepicker":
+		if action.SelectedDateTime == 0 {
+			return time.Time{}, nil
+		}
+		return time.Unix(action.SelectedDateTime, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("Cannot bind a %v element to time.Time", action.Type)
+}
+
+// Whether an element has a value.
+func hasValue(action slack.BlockAction) bool {
+	switch string(action.Type) {
+	case "multi_static_select", "multi_external_select", "checkboxes":
+		return len(action.SelectedOptions) > 0
+	case "multi_users_select":
+		return len(action.SelectedUsers) > 0
+	case "multi_conversations_select":
+		return len(action.SelectedConversations) > 0
+	case "multi_channels_select":
+		return len(action.SelectedChannels) > 0
+	}
+	return stringValue(action) != ""
+}
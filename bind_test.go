@@ -0,0 +1,167 @@
+package slap_test
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+type testForm struct {
+	Name      string                    `slap:"name/input"`
+	Age       int                       `slap:"age/input"`
+	Ratio     float64                   `slap:"ratio/input"`
+	Team      string                    `slap:"team/select"`
+	Option    slack.OptionBlockObject   `slap:"team/select"`
+	Tags      []string                  `slap:"tags/select"`
+	Reviewer  string                    `slap:"reviewer/select"`
+	Watchers  []string                  `slap:"watchers/select"`
+	Channel   string                    `slap:"channel/select"`
+	Due       time.Time                 `slap:"due/date"`
+	At        time.Time                 `slap:"at/time"`
+	Starts    time.Time                 `slap:"starts/datetime"`
+	Urgent    bool                      `slap:"urgent/checkbox"`
+	Choices   []slack.OptionBlockObject `slap:"choices/checkboxes"`
+	Email     mail.Address              `slap:"email/input"`
+	Website   *url.URL                  `slap:"website/input"`
+	Nickname  *string                   `slap:"nickname/input"`
+	Untracked string
+}
+
+func testFormState() map[string]map[string]slack.BlockAction {
+	option := func(value string) slack.OptionBlockObject {
+		return slack.OptionBlockObject{Value: value}
+	}
+	return map[string]map[string]slack.BlockAction{
+		"name":     {"input": {Type: "plain_text_input", Value: "Alice"}},
+		"age":      {"input": {Type: "number_input", Value: "42"}},
+		"ratio":    {"input": {Type: "number_input", Value: "0.5"}},
+		"team":     {"select": {Type: "static_select", SelectedOption: option("platform")}},
+		"tags":     {"select": {Type: "multi_static_select", SelectedOptions: []slack.OptionBlockObject{option("a"), option("b")}}},
+		"reviewer": {"select": {Type: "users_select", SelectedUser: "U0654321"}},
+		"watchers": {"select": {Type: "multi_users_select", SelectedUsers: []string{"U1", "U2"}}},
+		"channel":  {"select": {Type: "conversations_select", SelectedConversation: "C0654321"}},
+		"due":      {"date": {Type: "datepicker", SelectedDate: "2024-03-01"}},
+		"at":       {"time": {Type: "timepicker", SelectedTime: "09:30"}},
+		"starts":   {"datetime": {Type: "datetimepicker", SelectedDateTime: 1700000000}},
+		"urgent":   {"checkbox": {Type: "checkboxes", SelectedOptions: []slack.OptionBlockObject{option("yes")}}},
+		"choices":  {"checkboxes": {Type: "checkboxes", SelectedOptions: []slack.OptionBlockObject{option("x")}}},
+		"email":    {"input": {Type: "email_text_input", Value: "alice@example.com"}},
+		"website":  {"input": {Type: "url_text_input", Value: "https://example.com"}},
+		"nickname": {"input": {Type: "plain_text_input", Value: ""}},
+	}
+}
+
+func TestBindState(t *testing.T) {
+	t.Parallel()
+
+	var form testForm
+	if err := slap.BindState(testFormState(), &form); err != nil {
+		t.Fatalf("Could not bind state: %v", err.Error())
+	}
+
+	website, _ := url.Parse("https://example.com")
+	want := testForm{
+		Name:     "Alice",
+		Age:      42,
+		Ratio:    0.5,
+		Team:     "platform",
+		Option:   slack.OptionBlockObject{Value: "platform"},
+		Tags:     []string{"a", "b"},
+		Reviewer: "U0654321",
+		Watchers: []string{"U1", "U2"},
+		Channel:  "C0654321",
+		Due:      time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		At:       time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC),
+		Starts:   time.Unix(1700000000, 0),
+		Urgent:   true,
+		Choices:  []slack.OptionBlockObject{{Value: "x"}},
+		Email:    mail.Address{Address: "alice@example.com"},
+		Website:  website,
+		Nickname: nil,
+	}
+
+	if !reflect.DeepEqual(form, want) {
+		t.Errorf("Unexpected form, got: %+v, want: %+v", form, want)
+	}
+}
+
+func TestBindStateFieldErrors(t *testing.T) {
+	t.Parallel()
+
+	var form testForm
+	err := slap.BindState(map[string]map[string]slack.BlockAction{
+		"age":   {"input": {Type: "number_input", Value: "forty"}},
+		"email": {"input": {Type: "email_text_input", Value: "alice"}},
+	}, &form)
+
+	fields, ok := slap.ErrorFields(err)
+	if !ok {
+		t.Fatalf("Expected field errors, got: %v", err)
+	}
+
+	want := map[string]string{
+		"age":   "Enter a whole number.",
+		"email": "Enter a valid email address.",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Unexpected field errors, got: %v, want: %v", fields, want)
+	}
+}
+
+func TestBindStateInvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	var form testForm
+	if err := slap.BindState(nil, form); err == nil {
+		t.Errorf("Expected an error binding to a non-pointer")
+	}
+
+	var bad struct {
+		Value string `slap:"no-action"`
+	}
+	if err := slap.BindState(nil, &bad); err == nil {
+		t.Errorf("Expected an error for an invalid tag")
+	}
+
+	var unsupported struct {
+		Value map[string]string `slap:"block/action"`
+	}
+	err := slap.BindState(map[string]map[string]slack.BlockAction{"block": {"action": {Value: "x"}}}, &unsupported)
+	if err == nil || errors.As(err, new(*slap.Error)) {
+		t.Errorf("Expected an internal error for an unsupported type, got: %v", err)
+	}
+}
+
+func TestViewSubmissionBind(t *testing.T) {
+	t.Parallel()
+
+	forms := make(chan testForm, 1)
+	app, router := createTestApp()
+	app.RegisterViewSubmission("form", func(req *slap.ViewSubmissionRequest) error {
+		var form testForm
+		if err := req.Bind(&form); err != nil {
+			return err
+		}
+		forms <- form
+		req.Ack()
+		return nil
+	})
+
+	tester := slaptest.New(router, "signing-secret")
+	tester.ViewSubmission(slaptest.ViewSubmission{
+		View:   slack.View{CallbackID: "form"},
+		Values: testFormState(),
+	})
+
+	form := <-forms
+	if form.Name != "Alice" || form.Age != 42 {
+		t.Errorf("Unexpected form: %+v", form)
+	}
+}
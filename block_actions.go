@@ -14,7 +14,6 @@ type BlockActionPayload struct {
 	Actions        []slack.BlockAction `json:"actions"`
 	Hash           string              `json:"hash"`
 	BotAccessToken string              `json:"bot_access_token"`
-	Enterprise     *string             `json:"enterprise,omitempty"`
 	Channel        *struct {
 		ID   string `json:"id"`
 		Name string `json:"name"`
@@ -22,6 +21,23 @@ type BlockActionPayload struct {
 	Message *slack.MessageEvent      `json:"message,omitempty"`
 	View    *slack.View              `json:"view,omitempty"`
 	State   *slack.BlockActionStates `json:"state,omitempty"`
+	// A temporary webhook URL that used to generate message responses.
+	// Only sent for actions in messages.
+	ResponseURL string `json:"response_url,omitempty"`
+}
+
+func (p *BlockActionPayload) requestContext() RequestContext {
+	ctx := p.interactionPayload.requestContext()
+	ctx.ChannelID = p.Container.ChannelID
+	ctx.ThreadTimestamp = p.Container.ThreadTs
+	ctx.ResponseURL = p.ResponseURL
+	if p.Channel != nil && p.Channel.ID != "" {
+		ctx.ChannelID = p.Channel.ID
+	}
+	if ctx.ThreadTimestamp == "" && p.Message != nil {
+		ctx.ThreadTimestamp = p.Message.ThreadTimestamp
+	}
+	return ctx
 }
 
 // A block action request
@@ -47,13 +63,7 @@ func (app *Application) handleBlockActions(w http.ResponseWriter, blob []byte) {
 		return
 	}
 
-	botToken, err := app.botToken(payload.Team.ID)
-	if err != nil {
-		app.logger.Error("Could not get bot token", "teamID", payload.Team.ID, "error", err.Error())
-		http.Error(w, "Could not get bot token", http.StatusInternalServerError)
-		return
-	}
-
+	authorize := payload.authorizeContext()
 	actionID := payload.Actions[0].ActionID
 	handler, ok := app.blockActions[actionID]
 	if !ok {
@@ -69,24 +79,23 @@ func (app *Application) handleBlockActions(w http.ResponseWriter, blob []byte) {
 		req := &BlockActionRequest{
 			Payload: payload,
 			baseRequest: baseRequest{
-				errChannel: errChan,
-				ackChannel: ackChan,
-				ackCalled:  false,
-				writer:     w,
-				Client:     slack.New(botToken),
-				Logger:     app.logger,
+				errChannel:  errChan,
+				ackChannel:  ackChan,
+				ackCalled:   false,
+				writer:      w,
+				Logger:      app.logger,
+				app:         app,
+				authorize:   authorize,
+				responseURL: newResponseURL(payload.ResponseURL),
+				context:     payload.requestContext(),
 			},
 		}
 		err := handler(req)
 		if err == nil {
 			return
 		}
-		app.logger.Error("A block actions handler failed", "actionID", actionID, "error", err.Error())
-		_, msgerr := req.Client.PostEphemeral(req.Payload.Channel.ID, req.Payload.User.ID, slack.MsgOptionText(app.errorMessage, false))
-		if msgerr != nil {
-			app.logger.Error("Unable to send error message to user", "user", req.Payload.User.ID, "error", msgerr.Error())
-		}
-		errChan <- err
+		app.logger.Error("A block actions handler failed", "actionID", actionID, "code", ErrorCode(err), "error", err.Error())
+		app.handleError(req, BlockActionRequestKind, &req.Payload, err)
 	}()
 
 	select {
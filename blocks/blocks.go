@@ -0,0 +1,400 @@
+// This package builds Slack Block Kit blocks and modal views, checking
+// that their required fields are set.
+//
+//	blocks, err := blocks.Build(
+//		blocks.Header("Deploy"),
+//		blocks.Section(blocks.Markdown("*api* is ready to deploy")).
+//			Accessory(blocks.Button("deploy", "Deploy").Primary()),
+//	)
+package blocks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/slack-go/slack"
+)
+
+// A missing or invalid field of a block or element
+type Error struct {
+	// The path to the field, e.g. "blocks[1].accessory.text"
+	Path string
+	// What is wrong with the field
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Returns the Errors of an error returned by Build, e.g. to log
+// each path.
+func Errors(err error) []*Error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var found []*Error
+		for _, err := range joined.Unwrap() {
+			found = append(found, Errors(err)...)
+		}
+		return found
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return []*Error{e}
+	}
+	return nil
+}
+
+// A block builder
+type Block interface {
+	build(path string) (slack.Block, error)
+}
+
+// Creates a plain_text text object.
+func PlainText(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.PlainTextType, text, false, false)
+}
+
+// Creates a mrkdwn text object.
+func Markdown(text string) *slack.TextBlockObject {
+	return slack.NewTextBlockObject(slack.MarkdownType, text, false, false)
+}
+
+// Creates a mrkdwn text object with a formatted string.
+func Markdownf(format string, args ...any) *slack.TextBlockObject {
+	return Markdown(fmt.Sprintf(format, args...))
+}
+
+// Creates an option for selects, checkboxes and radio buttons.
+func Option(text string, value string) *slack.OptionBlockObject {
+	return slack.NewOptionBlockObject(value, PlainText(text), nil)
+}
+
+// Builds blocks for a message, e.g. CommandResponseAction.Blocks, or
+// a view.
+//
+// Returns an error listing every missing or invalid field, and every
+// field longer than its Block Kit limit. Use ValidateMessage to also
+// check the number of blocks in a message.
+func Build(blocks ...Block) ([]slack.Block, error) {
+	built := make([]slack.Block, 0, len(blocks))
+	var errs []error
+	for i, block := range blocks {
+		path := fmt.Sprintf("blocks[%v]", i)
+		b, err := block.build(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		limits := collector{path: path}
+		validateBlock(&limits, b)
+		if err := limits.err(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		built = append(built, b)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return built, nil
+}
+
+// Builds blocks like Build, but panics if a field is missing or
+// invalid. Useful for blocks that don't depend on input.
+func MustBuild(blocks ...Block) []slack.Block {
+	built, err := Build(blocks...)
+	if err != nil {
+		panic(err)
+	}
+	return built
+}
+
+// Collects the errors of a block or element.
+type collector struct {
+	path string
+	errs []error
+}
+
+func (c *collector) add(field string, message string) {
+	path := c.path
+	if path == "" {
+		path = field
+	} else if field != "" {
+		path += "." + field
+	}
+	c.errs = append(c.errs, &Error{Path: path, Message: message})
+}
+
+func (c *collector) require(field string, ok bool) {
+	if !ok {
+		c.add(field, "is required")
+	}
+}
+
+func (c *collector) merge(err error) {
+	if err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+func (c *collector) err() error {
+	return errors.Join(c.errs...)
+}
+
+func hasText(text *slack.TextBlockObject) bool {
+	return text != nil && text.Text != ""
+}
+
+// An existing slack.Block
+type rawBlock struct {
+	block slack.Block
+}
+
+// Uses an existing slack.Block, e.g. one without a builder.
+func Raw(block slack.Block) Block {
+	return rawBlock{block}
+}
+
+func (b rawBlock) build(path string) (slack.Block, error) {
+	if b.block == nil {
+		return nil, &Error{Path: path, Message: "is required"}
+	}
+	return b.block, nil
+}
+
+// A section block builder
+type SectionBuilder struct {
+	block     slack.SectionBlock
+	accessory Element
+}
+
+// Creates a section block with text. Use nil text with Fields.
+func Section(text *slack.TextBlockObject) *SectionBuilder {
+	return &SectionBuilder{block: slack.SectionBlock{Type: slack.MBTSection, Text: text}}
+}
+
+// Sets the block ID.
+func (b *SectionBuilder) ID(blockID string) *SectionBuilder {
+	b.block.BlockID = blockID
+	return b
+}
+
+// Adds fields, shown in two columns.
+func (b *SectionBuilder) Fields(fields ...*slack.TextBlockObject) *SectionBuilder {
+	b.block.Fields = append(b.block.Fields, fields...)
+	return b
+}
+
+// Sets an element shown beside the text, e.g. a button.
+func (b *SectionBuilder) Accessory(element Element) *SectionBuilder {
+	b.accessory = element
+	return b
+}
+
+func (b *SectionBuilder) build(path string) (slack.Block, error) {
+	c := collector{path: path}
+	block := b.block
+	if !hasText(block.Text) && len(block.Fields) == 0 {
+		c.add("text", "is required without fields")
+	}
+	for i, field := range block.Fields {
+		c.require(fmt.Sprintf("fields[%v]", i), hasText(field))
+	}
+	if b.accessory != nil {
+		element, err := b.accessory.build(path + ".accessory")
+		c.merge(err)
+		if err == nil {
+			block.Accessory = slack.NewAccessory(element)
+		}
+	}
+	return &block, c.err()
+}
+
+// A header block builder
+type HeaderBuilder struct {
+	block slack.HeaderBlock
+}
+
+// Creates a header block with plain text.
+func Header(text string) *HeaderBuilder {
+	return &HeaderBuilder{block: slack.HeaderBlock{Type: slack.MBTHeader, Text: PlainText(text)}}
+}
+
+// Sets the block ID.
+func (b *HeaderBuilder) ID(blockID string) *HeaderBuilder {
+	b.block.BlockID = blockID
+	return b
+}
+
+func (b *HeaderBuilder) build(path string) (slack.Block, error) {
+	c := collector{path: path}
+	c.require("text", hasText(b.block.Text))
+	block := b.block
+	return &block, c.err()
+}
+
+// A divider block builder
+type DividerBuilder struct {
+	block slack.DividerBlock
+}
+
+// Creates a divider block.
+func Divider() *DividerBuilder {
+	return &DividerBuilder{block: slack.DividerBlock{Type: slack.MBTDivider}}
+}
+
+// Sets the block ID.
+func (b *DividerBuilder) ID(blockID string) *DividerBuilder {
+	b.block.BlockID = blockID
+	return b
+}
+
+func (b *DividerBuilder) build(path string) (slack.Block, error) {
+	block := b.block
+	return &block, nil
+}
+
+// A context block builder
+type ContextBuilder struct {
+	block slack.ContextBlock
+}
+
+// Creates a context block with text and image elements, e.g.
+// Markdown("Updated by <@U0123456>").
+func Context(elements ...slack.MixedElement) *ContextBuilder {
+	return &ContextBuilder{block: slack.ContextBlock{
+		Type:            slack.MBTContext,
+		ContextElements: slack.ContextElements{Elements: elements},
+	}}
+}
+
+// Sets the block ID.
+func (b *ContextBuilder) ID(blockID string) *ContextBuilder {
+	b.block.BlockID = blockID
+	return b
+}
+
+func (b *ContextBuilder) build(path string) (slack.Block, error) {
+	c := collector{path: path}
+	c.require("elements", len(b.block.ContextElements.Elements) > 0)
+	for i, element := range b.block.ContextElements.Elements {
+		if text, ok := element.(*slack.TextBlockObject); ok {
+			c.require(fmt.Sprintf("elements[%v].text", i), hasText(text))
+		}
+	}
+	block := b.block
+	return &block, c.err()
+}
+
+// An actions block builder
+type ActionsBuilder struct {
+	blockID  string
+	elements []Element
+}
+
+// Creates an actions block with interactive elements, e.g. buttons.
+func Actions(elements ...Element) *ActionsBuilder {
+	return &ActionsBuilder{elements: elements}
+}
+
+// Sets the block ID.
+func (b *ActionsBuilder) ID(blockID string) *ActionsBuilder {
+	b.blockID = blockID
+	return b
+}
+
+func (b *ActionsBuilder) build(path string) (slack.Block, error) {
+	c := collector{path: path}
+	c.require("elements", len(b.elements) > 0)
+	elements := make([]slack.BlockElement, 0, len(b.elements))
+	for i, element := range b.elements {
+		e, err := element.build(fmt.Sprintf("%v.elements[%v]", path, i))
+		c.merge(err)
+		if err == nil {
+			elements = append(elements, e)
+		}
+	}
+	return slack.NewActionBlock(b.blockID, elements...), c.err()
+}
+
+// An input block builder
+type InputBuilder struct {
+	block   slack.InputBlock
+	element Element
+}
+
+// Creates an input block with a label and an input element.
+func Input(label string, element Element) *InputBuilder {
+	return &InputBuilder{
+		block:   slack.InputBlock{Type: slack.MBTInput, Label: PlainText(label)},
+		element: element,
+	}
+}
+
+// Sets the block ID.
+func (b *InputBuilder) ID(blockID string) *InputBuilder {
+	b.block.BlockID = blockID
+	return b
+}
+
+// Sets a hint shown below the input.
+func (b *InputBuilder) Hint(text string) *InputBuilder {
+	b.block.Hint = PlainText(text)
+	return b
+}
+
+// Allows the view to be submitted without a value.
+func (b *InputBuilder) Optional() *InputBuilder {
+	b.block.Optional = true
+	return b
+}
+
+// Sends a block action when the element's value changes.
+func (b *InputBuilder) DispatchAction() *InputBuilder {
+	b.block.DispatchAction = true
+	return b
+}
+
+func (b *InputBuilder) build(path string) (slack.Block, error) {
+	c := collector{path: path}
+	block := b.block
+	c.require("label", hasText(block.Label))
+	if b.element == nil {
+		c.add("element", "is required")
+	} else {
+		element, err := b.element.build(path + ".element")
+		c.merge(err)
+		block.Element = element
+	}
+	return &block, c.err()
+}
+
+// An image block builder
+type ImageBuilder struct {
+	block slack.ImageBlock
+}
+
+// Creates an image block.
+func Image(imageURL string, altText string) *ImageBuilder {
+	return &ImageBuilder{block: slack.ImageBlock{Type: slack.MBTImage, ImageURL: imageURL, AltText: altText}}
+}
+
+// Sets the block ID.
+func (b *ImageBuilder) ID(blockID string) *ImageBuilder {
+	b.block.BlockID = blockID
+	return b
+}
+
+// Sets a title shown above the image.
+func (b *ImageBuilder) Title(text string) *ImageBuilder {
+	b.block.Title = PlainText(text)
+	return b
+}
+
+func (b *ImageBuilder) build(path string) (slack.Block, error) {
+	c := collector{path: path}
+	c.require("image_url", b.block.ImageURL != "")
+	c.require("alt_text", b.block.AltText != "")
+	block := b.block
+	return &block, c.err()
+}
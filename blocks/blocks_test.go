@@ -0,0 +1,144 @@
+package blocks_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/blocks"
+	"github.com/slack-go/slack"
+)
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	built, err := blocks.Build(
+		blocks.Header("Deploy"),
+		blocks.Section(blocks.Markdown("*api* is ready")).
+			Accessory(blocks.Button("deploy", "Deploy").Value("api").Primary()),
+		blocks.Divider(),
+		blocks.Context(blocks.Markdown("Requested by <@U0123456>")),
+		blocks.Actions(
+			blocks.StaticSelect("env", "Environment", blocks.Option("Staging", "staging"), blocks.Option("Production", "production")).
+				Initial("staging"),
+			blocks.Button("cancel", "Cancel").Danger(),
+		),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	countGot, countWant := len(built), 5
+	if countGot != countWant {
+		t.Fatalf("Unexpected number of blocks, got: %v, want: %v", countGot, countWant)
+	}
+
+	section, ok := built[1].(*slack.SectionBlock)
+	if !ok {
+		t.Fatalf("Expected a section block, got: %T", built[1])
+	}
+	styleGot, styleWant := section.Accessory.ButtonElement.Style, slack.StylePrimary
+	if styleGot != styleWant {
+		t.Errorf("Unexpected button style, got: %v, want: %v", styleGot, styleWant)
+	}
+
+	actions := built[4].(*slack.ActionBlock)
+	selectElement := actions.Elements.ElementSet[0].(*slack.SelectBlockElement)
+	initialGot, initialWant := selectElement.InitialOption.Value, "staging"
+	if initialGot != initialWant {
+		t.Errorf("Unexpected initial option, got: %v, want: %v", initialGot, initialWant)
+	}
+
+	// The blocks can be sent in a response action
+	bytes, err := json.Marshal(slap.CommandResponseAction{ResponseType: slap.RespondEphemeral, Blocks: built})
+	if err != nil {
+		t.Fatalf("Could not encode blocks: %v", err.Error())
+	}
+	if !strings.Contains(string(bytes), `"type":"static_select"`) {
+		t.Errorf("Expected the select to be encoded, got: %v", string(bytes))
+	}
+}
+
+func TestBuildRequiredFields(t *testing.T) {
+	t.Parallel()
+
+	_, err := blocks.Build(
+		blocks.Section(nil),
+		blocks.Actions(blocks.Button("ok", "")),
+		blocks.Input("Reviewer", blocks.StaticSelect("reviewer", "Pick one")),
+		blocks.Image("", "A chart"),
+	)
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+
+	var paths []string
+	for _, e := range blocks.Errors(err) {
+		paths = append(paths, e.Path)
+	}
+	pathsGot := strings.Join(paths, ",")
+	pathsWant := "blocks[0].text,blocks[1].elements[0].text,blocks[2].element.options,blocks[3].image_url"
+	if pathsGot != pathsWant {
+		t.Errorf("Unexpected error paths, got: %v, want: %v", pathsGot, pathsWant)
+	}
+}
+
+func TestBuildUnknownInitialOption(t *testing.T) {
+	t.Parallel()
+
+	_, err := blocks.Build(blocks.Input("Colours", blocks.Checkboxes("colours", blocks.Option("Red", "red")).Initial("blue")))
+
+	errGot, errWant := err.Error(), `blocks[0].element.initial_options: "blue" is not an option`
+	if errGot != errWant {
+		t.Errorf("Unexpected error, got: %v, want: %v", errGot, errWant)
+	}
+}
+
+func TestModal(t *testing.T) {
+	t.Parallel()
+
+	due := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	view, err := blocks.Modal("New ticket").
+		CallbackID("ticket-modal").
+		Submit("Create").
+		NotifyOnClose().
+		Blocks(
+			blocks.Input("Title", blocks.TextInput("title-input").Length(3, 80)).ID("title-block"),
+			blocks.Input("Due", blocks.DatePicker("due-datepicker").Initial(due)).ID("due-block").Optional(),
+			blocks.Input("Assignee", blocks.UsersSelect("user-select", "Choose someone").Initial("U0123456")).ID("assignee-block"),
+		).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	callbackGot, callbackWant := view.CallbackID, "ticket-modal"
+	if callbackGot != callbackWant {
+		t.Errorf("Unexpected callback ID, got: %v, want: %v", callbackGot, callbackWant)
+	}
+
+	input := view.Blocks.BlockSet[1].(*slack.InputBlock)
+	dateGot, dateWant := input.Element.(*slack.DatePickerBlockElement).InitialDate, "2024-03-01"
+	if dateGot != dateWant {
+		t.Errorf("Unexpected initial date, got: %v, want: %v", dateGot, dateWant)
+	}
+}
+
+func TestModalRequiresSubmit(t *testing.T) {
+	t.Parallel()
+
+	_, err := blocks.Modal("").
+		Blocks(blocks.Input("Title", blocks.TextInput("title-input"))).
+		View()
+
+	var paths []string
+	for _, e := range blocks.Errors(err) {
+		paths = append(paths, e.Path)
+	}
+	pathsGot, pathsWant := strings.Join(paths, ","), "view.title,view.submit"
+	if pathsGot != pathsWant {
+		t.Errorf("Unexpected error paths, got: %v, want: %v", pathsGot, pathsWant)
+	}
+}
@@ -0,0 +1,458 @@
+package blocks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// An element builder
+type Element interface {
+	build(path string) (slack.BlockElement, error)
+}
+
+// A button element builder
+type ButtonBuilder struct {
+	element slack.ButtonBlockElement
+}
+
+// Creates a button with plain text.
+func Button(actionID string, text string) *ButtonBuilder {
+	return &ButtonBuilder{element: slack.ButtonBlockElement{
+		Type:     slack.METButton,
+		ActionID: actionID,
+		Text:     PlainText(text),
+	}}
+}
+
+// Sets the value sent with the block action.
+func (b *ButtonBuilder) Value(value string) *ButtonBuilder {
+	b.element.Value = value
+	return b
+}
+
+// Sets a URL opened by the button.
+func (b *ButtonBuilder) URL(url string) *ButtonBuilder {
+	b.element.URL = url
+	return b
+}
+
+// Styles the button as the primary action.
+func (b *ButtonBuilder) Primary() *ButtonBuilder {
+	b.element.Style = slack.StylePrimary
+	return b
+}
+
+// Styles the button as a destructive action.
+func (b *ButtonBuilder) Danger() *ButtonBuilder {
+	b.element.Style = slack.StyleDanger
+	return b
+}
+
+// Asks the user to confirm before the action is sent.
+func (b *ButtonBuilder) Confirm(title string, text string, confirm string, deny string) *ButtonBuilder {
+	b.element.Confirm = confirmation(title, text, confirm, deny)
+	return b
+}
+
+func (b *ButtonBuilder) build(path string) (slack.BlockElement, error) {
+	c := collector{path: path}
+	c.require("text", hasText(b.element.Text))
+	element := b.element
+	return &element, c.err()
+}
+
+// A select menu builder
+type SelectBuilder struct {
+	element slack.SelectBlockElement
+	initial string
+}
+
+// Creates a select menu of options.
+func StaticSelect(actionID string, placeholder string, options ...*slack.OptionBlockObject) *SelectBuilder {
+	return newSelect(slack.OptTypeStatic, actionID, placeholder).Options(options...)
+}
+
+// Creates a select menu of options loaded from the app's options
+// load URL.
+func ExternalSelect(actionID string, placeholder string) *SelectBuilder {
+	return newSelect(slack.OptTypeExternal, actionID, placeholder)
+}
+
+// Creates a select menu of users.
+func UsersSelect(actionID string, placeholder string) *SelectBuilder {
+	return newSelect(slack.OptTypeUser, actionID, placeholder)
+}
+
+// Creates a select menu of conversations.
+func ConversationsSelect(actionID string, placeholder string) *SelectBuilder {
+	return newSelect(slack.OptTypeConversations, actionID, placeholder)
+}
+
+// Creates a select menu of public channels.
+func ChannelsSelect(actionID string, placeholder string) *SelectBuilder {
+	return newSelect(slack.OptTypeChannels, actionID, placeholder)
+}
+
+func newSelect(optType string, actionID string, placeholder string) *SelectBuilder {
+	return &SelectBuilder{element: slack.SelectBlockElement{
+		Type:        optType,
+		ActionID:    actionID,
+		Placeholder: PlainText(placeholder),
+	}}
+}
+
+// Adds options to a static select.
+func (b *SelectBuilder) Options(options ...*slack.OptionBlockObject) *SelectBuilder {
+	b.element.Options = append(b.element.Options, options...)
+	return b
+}
+
+// Sets the initially selected value: an option's value, or a user,
+// conversation or channel ID.
+func (b *SelectBuilder) Initial(value string) *SelectBuilder {
+	b.initial = value
+	return b
+}
+
+func (b *SelectBuilder) build(path string) (slack.BlockElement, error) {
+	c := collector{path: path}
+	element := b.element
+	if element.Type == slack.OptTypeStatic {
+		c.require("options", len(element.Options) > 0)
+		checkOptions(&c, element.Options)
+	}
+	if b.initial != "" {
+		switch element.Type {
+		case slack.OptTypeStatic:
+			element.InitialOption = findOption(&c, element.Options, b.initial)
+		case slack.OptTypeExternal:
+			c.add("initial_option", "is not supported by external selects")
+		case slack.OptTypeUser:
+			element.InitialUser = b.initial
+		case slack.OptTypeConversations:
+			element.InitialConversation = b.initial
+		case slack.OptTypeChannels:
+			element.InitialChannel = b.initial
+		}
+	}
+	return &element, c.err()
+}
+
+// A multi-select menu builder
+type MultiSelectBuilder struct {
+	element slack.MultiSelectBlockElement
+	initial []string
+}
+
+// Creates a multi-select menu of options.
+func MultiStaticSelect(actionID string, placeholder string, options ...*slack.OptionBlockObject) *MultiSelectBuilder {
+	return newMultiSelect(slack.MultiOptTypeStatic, actionID, placeholder).Options(options...)
+}
+
+// Creates a multi-select menu of users.
+func MultiUsersSelect(actionID string, placeholder string) *MultiSelectBuilder {
+	return newMultiSelect(slack.MultiOptTypeUser, actionID, placeholder)
+}
+
+// Creates a multi-select menu of conversations.
+func MultiConversationsSelect(actionID string, placeholder string) *MultiSelectBuilder {
+	return newMultiSelect(slack.MultiOptTypeConversations, actionID, placeholder)
+}
+
+// Creates a multi-select menu of public channels.
+func MultiChannelsSelect(actionID string, placeholder string) *MultiSelectBuilder {
+	return newMultiSelect(slack.MultiOptTypeChannels, actionID, placeholder)
+}
+
+func newMultiSelect(optType string, actionID string, placeholder string) *MultiSelectBuilder {
+	return &MultiSelectBuilder{element: slack.MultiSelectBlockElement{
+		Type:        optType,
+		ActionID:    actionID,
+		Placeholder: PlainText(placeholder),
+	}}
+}
+
+// Adds options to a static multi-select.
+func (b *MultiSelectBuilder) Options(options ...*slack.OptionBlockObject) *MultiSelectBuilder {
+	b.element.Options = append(b.element.Options, options...)
+	return b
+}
+
+// Sets the initially selected values: options' values, or user,
+// conversation or channel IDs.
+func (b *MultiSelectBuilder) Initial(values ...string) *MultiSelectBuilder {
+	b.initial = values
+	return b
+}
+
+// Sets the maximum number of values that can be selected.
+func (b *MultiSelectBuilder) MaxSelected(max int) *MultiSelectBuilder {
+	b.element.MaxSelectedItems = &max
+	return b
+}
+
+func (b *MultiSelectBuilder) build(path string) (slack.BlockElement, error) {
+	c := collector{path: path}
+	element := b.element
+	if element.Type == slack.MultiOptTypeStatic {
+		c.require("options", len(element.Options) > 0)
+		checkOptions(&c, element.Options)
+	}
+	if len(b.initial) > 0 {
+		switch element.Type {
+		case slack.MultiOptTypeStatic:
+			element.InitialOptions = findOptions(&c, element.Options, b.initial)
+		case slack.MultiOptTypeUser:
+			element.InitialUsers = b.initial
+		case slack.MultiOptTypeConversations:
+			element.InitialConversations = b.initial
+		case slack.MultiOptTypeChannels:
+			element.InitialChannels = b.initial
+		}
+	}
+	if element.MaxSelectedItems != nil && *element.MaxSelectedItems < 1 {
+		c.add("max_selected_items", "must be at least 1")
+	}
+	return &element, c.err()
+}
+
+// An overflow menu builder
+type OverflowBuilder struct {
+	element slack.OverflowBlockElement
+}
+
+// Creates an overflow menu of options.
+func Overflow(actionID string, options ...*slack.OptionBlockObject) *OverflowBuilder {
+	return &OverflowBuilder{element: slack.OverflowBlockElement{
+		Type:     slack.METOverflow,
+		ActionID: actionID,
+		Options:  options,
+	}}
+}
+
+func (b *OverflowBuilder) build(path string) (slack.BlockElement, error) {
+	c := collector{path: path}
+	c.require("options", len(b.element.Options) > 0)
+	checkOptions(&c, b.element.Options)
+	element := b.element
+	return &element, c.err()
+}
+
+// A date picker builder
+type DatePickerBuilder struct {
+	element slack.DatePickerBlockElement
+}
+
+// Creates a date picker.
+func DatePicker(actionID string) *DatePickerBuilder {
+	return &DatePickerBuilder{element: slack.DatePickerBlockElement{
+		Type:     slack.METDatepicker,
+		ActionID: actionID,
+	}}
+}
+
+// Sets the placeholder shown without a date.
+func (b *DatePickerBuilder) Placeholder(text string) *DatePickerBuilder {
+	b.element.Placeholder = PlainText(text)
+	return b
+}
+
+// Sets the initially selected date.
+func (b *DatePickerBuilder) Initial(date time.Time) *DatePickerBuilder {
+	b.element.InitialDate = date.Format(time.DateOnly)
+	return b
+}
+
+func (b *DatePickerBuilder) build(path string) (slack.BlockElement, error) {
+	element := b.element
+	return &element, nil
+}
+
+// A time picker builder
+type TimePickerBuilder struct {
+	element slack.TimePickerBlockElement
+}
+
+// Creates a time picker.
+func TimePicker(actionID string) *TimePickerBuilder {
+	return &TimePickerBuilder{element: slack.TimePickerBlockElement{
+		Type:     slack.METTimepicker,
+		ActionID: actionID,
+	}}
+}
+
+// Sets the placeholder shown without a time.
+func (b *TimePickerBuilder) Placeholder(text string) *TimePickerBuilder {
+	b.element.Placeholder = PlainText(text)
+	return b
+}
+
+// Sets the initially selected time, ignoring the date.
+func (b *TimePickerBuilder) Initial(t time.Time) *TimePickerBuilder {
+	b.element.InitialTime = t.Format("15:04")
+	return b
+}
+
+func (b *TimePickerBuilder) build(path string) (slack.BlockElement, error) {
+	element := b.element
+	return &element, nil
+}
+
+// A plain text input builder
+type TextInputBuilder struct {
+	element slack.PlainTextInputBlockElement
+}
+
+// Creates a plain text input.
+func TextInput(actionID string) *TextInputBuilder {
+	return &TextInputBuilder{element: slack.PlainTextInputBlockElement{
+		Type:     slack.METPlainTextInput,
+		ActionID: actionID,
+	}}
+}
+
+// Sets the placeholder shown without a value.
+func (b *TextInputBuilder) Placeholder(text string) *TextInputBuilder {
+	b.element.Placeholder = PlainText(text)
+	return b
+}
+
+// Sets the initial value.
+func (b *TextInputBuilder) Initial(value string) *TextInputBuilder {
+	b.element.InitialValue = value
+	return b
+}
+
+// Shows a larger input for multiple lines.
+func (b *TextInputBuilder) Multiline() *TextInputBuilder {
+	b.element.Multiline = true
+	return b
+}
+
+// Sets the minimum and maximum length of the value. A max of 0
+// means no maximum.
+func (b *TextInputBuilder) Length(min int, max int) *TextInputBuilder {
+	b.element.MinLength = min
+	b.element.MaxLength = max
+	return b
+}
+
+func (b *TextInputBuilder) build(path string) (slack.BlockElement, error) {
+	c := collector{path: path}
+	element := b.element
+	if element.MinLength < 0 {
+		c.add("min_length", "must not be negative")
+	}
+	if element.MaxLength > 0 && element.MaxLength < element.MinLength {
+		c.add("max_length", "must not be less than min_length")
+	}
+	return &element, c.err()
+}
+
+// A checkboxes builder
+type CheckboxesBuilder struct {
+	element slack.CheckboxGroupsBlockElement
+	initial []string
+}
+
+// Creates a group of checkboxes.
+func Checkboxes(actionID string, options ...*slack.OptionBlockObject) *CheckboxesBuilder {
+	return &CheckboxesBuilder{element: slack.CheckboxGroupsBlockElement{
+		Type:     slack.METCheckboxGroups,
+		ActionID: actionID,
+		Options:  options,
+	}}
+}
+
+// Sets the values of the initially checked options.
+func (b *CheckboxesBuilder) Initial(values ...string) *CheckboxesBuilder {
+	b.initial = values
+	return b
+}
+
+func (b *CheckboxesBuilder) build(path string) (slack.BlockElement, error) {
+	c := collector{path: path}
+	element := b.element
+	c.require("options", len(element.Options) > 0)
+	checkOptions(&c, element.Options)
+	if len(b.initial) > 0 {
+		element.InitialOptions = findOptions(&c, element.Options, b.initial)
+	}
+	return &element, c.err()
+}
+
+// A radio buttons builder
+type RadioButtonsBuilder struct {
+	element slack.RadioButtonsBlockElement
+	initial string
+}
+
+// Creates a group of radio buttons.
+func RadioButtons(actionID string, options ...*slack.OptionBlockObject) *RadioButtonsBuilder {
+	return &RadioButtonsBuilder{element: slack.RadioButtonsBlockElement{
+		Type:     slack.METRadioButtons,
+		ActionID: actionID,
+		Options:  options,
+	}}
+}
+
+// Sets the value of the initially selected option.
+func (b *RadioButtonsBuilder) Initial(value string) *RadioButtonsBuilder {
+	b.initial = value
+	return b
+}
+
+func (b *RadioButtonsBuilder) build(path string) (slack.BlockElement, error) {
+	c := collector{path: path}
+	element := b.element
+	c.require("options", len(element.Options) > 0)
+	checkOptions(&c, element.Options)
+	if b.initial != "" {
+		element.InitialOption = findOption(&c, element.Options, b.initial)
+	}
+	return &element, c.err()
+}
+
+func confirmation(title string, text string, confirm string, deny string) *slack.ConfirmationBlockObject {
+	return slack.NewConfirmationBlockObject(PlainText(title), PlainText(text), PlainText(confirm), PlainText(deny))
+}
+
+func checkOptions(c *collector, options []*slack.OptionBlockObject) {
+	for i, option := range options {
+		field := fmt.Sprintf("options[%v]", i)
+		if option == nil {
+			c.add(field, "is required")
+			continue
+		}
+		c.require(field+".text", hasText(option.Text))
+		c.require(field+".value", option.Value != "")
+	}
+}
+
+func findOption(c *collector, options []*slack.OptionBlockObject, value string) *slack.OptionBlockObject {
+	for _, option := range options {
+		if option != nil && option.Value == value {
+			return option
+		}
+	}
+	c.add("initial_option", fmt.Sprintf("%q is not an option", value))
+	return nil
+}
+
+func findOptions(c *collector, options []*slack.OptionBlockObject, values []string) []*slack.OptionBlockObject {
+	found := make([]*slack.OptionBlockObject, 0, len(values))
+	for _, value := range values {
+		for _, option := range options {
+			if option != nil && option.Value == value {
+				found = append(found, option)
+				break
+			}
+		}
+		if len(found) == 0 || found[len(found)-1].Value != value {
+			c.add("initial_options", fmt.Sprintf("%q is not an option", value))
+		}
+	}
+	return found
+}
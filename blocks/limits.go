@@ -0,0 +1,277 @@
+package blocks
+
+import (
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+
+	"github.com/slack-go/slack"
+)
+
+// Block Kit limits
+const (
+	// The maximum number of blocks in a message
+	MaxMessageBlocks = 50
+	// The maximum number of blocks in a modal or Home tab
+	MaxViewBlocks = 100
+	// The maximum length of a message's text
+	MaxMessageTextLength = 40000
+	// The maximum length of a view's title, submit and close text
+	MaxViewTitleLength = 24
+	// The maximum length of a view's private_metadata
+	MaxPrivateMetadataLength = 3000
+	// The maximum length of block, action, callback and external IDs
+	MaxIDLength = 255
+	// The maximum length of a section's text and most other text
+	MaxTextLength = 3000
+)
+
+// Checks a message's text and blocks against Block Kit limits, e.g.
+// for a CommandResponseAction.
+//
+// Returns an error listing every violation. See Errors.
+func ValidateMessage(text string, blocks []slack.Block) error {
+	c := collector{}
+	c.max("text", text, MaxMessageTextLength)
+	if len(blocks) > MaxMessageBlocks {
+		c.add("blocks", fmt.Sprintf("must have at most %v blocks, has %v", MaxMessageBlocks, len(blocks)))
+	}
+	validateBlocks(&c, blocks)
+	return c.err()
+}
+
+// Checks a modal view against Block Kit limits.
+//
+// Returns an error listing every violation. See Errors.
+func ValidateView(view slack.ModalViewRequest) error {
+	c := collector{path: "view"}
+	c.maxText("title", view.Title, MaxViewTitleLength)
+	c.maxText("submit", view.Submit, MaxViewTitleLength)
+	c.maxText("close", view.Close, MaxViewTitleLength)
+	c.max("private_metadata", view.PrivateMetadata, MaxPrivateMetadataLength)
+	c.max("callback_id", view.CallbackID, MaxIDLength)
+	c.max("external_id", view.ExternalID, MaxIDLength)
+	if len(view.Blocks.BlockSet) > MaxViewBlocks {
+		c.add("blocks", fmt.Sprintf("must have at most %v blocks, has %v", MaxViewBlocks, len(view.Blocks.BlockSet)))
+	}
+	validateBlocks(&c, view.Blocks.BlockSet)
+	return c.err()
+}
+
+// Adds an error if a value is longer than max characters.
+func (c *collector) max(field string, value string, max int) {
+	if length := utf8.RuneCountInString(value); length > max {
+		c.add(field, fmt.Sprintf("must be at most %v characters, has %v", max, length))
+	}
+}
+
+func (c *collector) maxText(field string, text *slack.TextBlockObject, max int) {
+	if text != nil {
+		c.max(field+".text", text.Text, max)
+	}
+}
+
+func (c *collector) maxItems(field string, count int, max int) {
+	if count > max {
+		c.add(field, fmt.Sprintf("must have at most %v items, has %v", max, count))
+	}
+}
+
+// Returns a collector for a nested field.
+func (c *collector) at(field string) *collector {
+	path := field
+	if c.path != "" {
+		path = c.path + "." + field
+	}
+	return &collector{path: path}
+}
+
+func validateBlocks(c *collector, blocks []slack.Block) {
+	for i, block := range blocks {
+		b := c.at(fmt.Sprintf("blocks[%v]", i))
+		validateBlock(b, block)
+		c.errs = append(c.errs, b.errs...)
+	}
+}
+
+// Checks a block's fields against their limits.
+func validateBlock(c *collector, block slack.Block) {
+	switch b := pointerTo(block).(type) {
+	case *slack.SectionBlock:
+		c.max("block_id", b.BlockID, MaxIDLength)
+		c.maxText("text", b.Text, MaxTextLength)
+		c.maxItems("fields", len(b.Fields), 10)
+		for i, field := range b.Fields {
+			c.maxText(fmt.Sprintf("fields[%v]", i), field, 2000)
+		}
+		if b.Accessory != nil {
+			if element := accessoryElement(b.Accessory); element != nil {
+				validateElement(c, "accessory", element)
+			}
+		}
+	case *slack.HeaderBlock:
+		c.max("block_id", b.BlockID, MaxIDLength)
+		c.maxText("text", b.Text, 150)
+	case *slack.DividerBlock:
+		c.max("block_id", b.BlockID, MaxIDLength)
+	case *slack.ContextBlock:
+		c.max("block_id", b.BlockID, MaxIDLength)
+		c.maxItems("elements", len(b.ContextElements.Elements), 10)
+		for i, element := range b.ContextElements.Elements {
+			field := fmt.Sprintf("elements[%v]", i)
+			switch e := pointerTo(element).(type) {
+			case *slack.TextBlockObject:
+				c.maxText(field, e, MaxTextLength)
+			case *slack.ImageBlockElement:
+				c.max(field+".image_url", e.ImageURL, 3000)
+				c.max(field+".alt_text", e.AltText, 2000)
+			}
+		}
+	case *slack.ActionBlock:
+		c.max("block_id", b.BlockID, MaxIDLength)
+		if b.Elements != nil {
+			c.maxItems("elements", len(b.Elements.ElementSet), 25)
+			for i, element := range b.Elements.ElementSet {
+				validateElement(c, fmt.Sprintf("elements[%v]", i), element)
+			}
+		}
+	case *slack.InputBlock:
+		c.max("block_id", b.BlockID, MaxIDLength)
+		c.maxText("label", b.Label, 2000)
+		c.maxText("hint", b.Hint, 2000)
+		if b.Element != nil {
+			validateElement(c, "element", b.Element)
+		}
+	case *slack.ImageBlock:
+		c.max("block_id", b.BlockID, MaxIDLength)
+		c.max("image_url", b.ImageURL, 3000)
+		c.max("alt_text", b.AltText, 2000)
+		c.maxText("title", b.Title, 2000)
+	}
+}
+
+// Checks an element's fields against their limits.
+func validateElement(parent *collector, field string, element slack.BlockElement) {
+	c := parent.at(field)
+	defer func() { parent.errs = append(parent.errs, c.errs...) }()
+
+	switch e := pointerTo(element).(type) {
+	case *slack.ButtonBlockElement:
+		c.max("action_id", e.ActionID, MaxIDLength)
+		c.maxText("text", e.Text, 75)
+		c.max("value", e.Value, 2000)
+		c.max("url", e.URL, 3000)
+		validateConfirm(c, e.Confirm)
+	case *slack.SelectBlockElement:
+		c.max("action_id", e.ActionID, MaxIDLength)
+		c.maxText("placeholder", e.Placeholder, 150)
+		c.maxItems("options", len(e.Options), 100)
+		validateOptions(c, "options", e.Options)
+		c.maxItems("option_groups", len(e.OptionGroups), 100)
+		validateConfirm(c, e.Confirm)
+	case *slack.MultiSelectBlockElement:
+		c.max("action_id", e.ActionID, MaxIDLength)
+		c.maxText("placeholder", e.Placeholder, 150)
+		c.maxItems("options", len(e.Options), 100)
+		validateOptions(c, "options", e.Options)
+		c.maxItems("option_groups", len(e.OptionGroups), 100)
+		validateConfirm(c, e.Confirm)
+	case *slack.OverflowBlockElement:
+		c.max("action_id", e.ActionID, MaxIDLength)
+		c.maxItems("options", len(e.Options), 5)
+		validateOptions(c, "options", e.Options)
+		validateConfirm(c, e.Confirm)
+	case *slack.CheckboxGroupsBlockElement:
+		c.max("action_id", e.ActionID, MaxIDLength)
+		c.maxItems("options", len(e.Options), 10)
+		validateOptions(c, "options", e.Options)
+		validateConfirm(c, e.Confirm)
+	case *slack.RadioButtonsBlockElement:
+		c.max("action_id", e.ActionID, MaxIDLength)
+		c.maxItems("options", len(e.Options), 10)
+		validateOptions(c, "options", e.Options)
+		validateConfirm(c, e.Confirm)
+	case *slack.DatePickerBlockElement:
+		c.max("action_id", e.ActionID, MaxIDLength)
+		c.maxText("placeholder", e.Placeholder, 150)
+		validateConfirm(c, e.Confirm)
+	case *slack.TimePickerBlockElement:
+		c.max("action_id", e.ActionID, MaxIDLength)
+		c.maxText("placeholder", e.Placeholder, 150)
+		validateConfirm(c, e.Confirm)
+	case *slack.PlainTextInputBlockElement:
+		c.max("action_id", e.ActionID, MaxIDLength)
+		c.maxText("placeholder", e.Placeholder, 150)
+		if e.MaxLength > 3000 {
+			c.add("max_length", fmt.Sprintf("must be at most 3000, is %v", e.MaxLength))
+		}
+	case *slack.ImageBlockElement:
+		c.max("image_url", e.ImageURL, 3000)
+		c.max("alt_text", e.AltText, 2000)
+	}
+}
+
+func validateOptions(c *collector, field string, options []*slack.OptionBlockObject) {
+	for i, option := range options {
+		if option == nil {
+			continue
+		}
+		path := fmt.Sprintf("%v[%v]", field, i)
+		c.maxText(path+".text", option.Text, 75)
+		c.max(path+".value", option.Value, 150)
+		c.maxText(path+".description", option.Description, 75)
+	}
+}
+
+func validateConfirm(c *collector, confirm *slack.ConfirmationBlockObject) {
+	if confirm == nil {
+		return
+	}
+	c.maxText("confirm.title", confirm.Title, 100)
+	c.maxText("confirm.text", confirm.Text, 300)
+	c.maxText("confirm.confirm", confirm.Confirm, 30)
+	c.maxText("confirm.deny", confirm.Deny, 30)
+}
+
+// A pointer to a copy of a block or element that isn't a pointer,
+// e.g. *slack.SectionBlock for a slack.SectionBlock, so that both
+// can be checked.
+func pointerTo[T any](v T) T {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Struct {
+		return v
+	}
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+	if p, ok := pointer.Interface().(T); ok {
+		return p
+	}
+	return v
+}
+
+// The element of a section's accessory.
+func accessoryElement(accessory *slack.Accessory) slack.BlockElement {
+	switch {
+	case accessory.ImageElement != nil:
+		return accessory.ImageElement
+	case accessory.ButtonElement != nil:
+		return accessory.ButtonElement
+	case accessory.OverflowElement != nil:
+		return accessory.OverflowElement
+	case accessory.DatePickerElement != nil:
+		return accessory.DatePickerElement
+	case accessory.TimePickerElement != nil:
+		return accessory.TimePickerElement
+	case accessory.PlainTextInputElement != nil:
+		return accessory.PlainTextInputElement
+	case accessory.RadioButtonsElement != nil:
+		return accessory.RadioButtonsElement
+	case accessory.SelectElement != nil:
+		return accessory.SelectElement
+	case accessory.MultiSelectElement != nil:
+		return accessory.MultiSelectElement
+	case accessory.CheckboxGroupsBlockElement != nil:
+		return accessory.CheckboxGroupsBlockElement
+	}
+	return nil
+}
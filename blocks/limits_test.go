@@ -0,0 +1,86 @@
+package blocks_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jacob-ian/slap/blocks"
+	"github.com/slack-go/slack"
+)
+
+func TestValidateMessage(t *testing.T) {
+	t.Parallel()
+
+	many := make([]slack.Block, 51)
+	for i := range many {
+		many[i] = slack.NewDividerBlock()
+	}
+	many[3] = slack.NewSectionBlock(blocks.Markdown(strings.Repeat("a", 3001)), nil, nil)
+
+	var paths []string
+	for _, e := range blocks.Errors(blocks.ValidateMessage("", many)) {
+		paths = append(paths, e.Path)
+	}
+	pathsGot, pathsWant := strings.Join(paths, ","), "blocks,blocks[3].text.text"
+	if pathsGot != pathsWant {
+		t.Errorf("Unexpected error paths, got: %v, want: %v", pathsGot, pathsWant)
+	}
+}
+
+func TestValidateMessageValueBlocks(t *testing.T) {
+	t.Parallel()
+
+	button := slack.ButtonBlockElement{Type: "button", ActionID: strings.Repeat("a", 256), Text: blocks.PlainText("Go")}
+	err := blocks.ValidateMessage("", []slack.Block{
+		slack.SectionBlock{Type: "section", Text: blocks.PlainText(strings.Repeat("a", 4000))},
+		slack.InputBlock{Type: "input", Label: blocks.PlainText("Name"), Element: slack.PlainTextInputBlockElement{Type: "plain_text_input", MaxLength: 4000}},
+		slack.ActionBlock{Type: "actions", Elements: &slack.BlockElements{ElementSet: []slack.BlockElement{button}}},
+	})
+
+	var paths []string
+	for _, e := range blocks.Errors(err) {
+		paths = append(paths, e.Path)
+	}
+	pathsGot, pathsWant := strings.Join(paths, ","), "blocks[0].text.text,blocks[1].element.max_length,blocks[2].elements[0].action_id"
+	if pathsGot != pathsWant {
+		t.Errorf("Unexpected error paths, got: %v, want: %v", pathsGot, pathsWant)
+	}
+}
+
+func TestValidateView(t *testing.T) {
+	t.Parallel()
+
+	button := slack.NewButtonBlockElement(strings.Repeat("a", 256), "", blocks.PlainText("Go"))
+	err := blocks.ValidateView(slack.ModalViewRequest{
+		Type:   slack.VTModal,
+		Title:  blocks.PlainText("A title that is far too long"),
+		Blocks: slack.Blocks{BlockSet: []slack.Block{slack.NewActionBlock("", button)}},
+	})
+
+	errs := blocks.Errors(err)
+	countGot, countWant := len(errs), 2
+	if countGot != countWant {
+		t.Fatalf("Unexpected number of errors, got: %v, want: %v", countGot, countWant)
+	}
+
+	errGot, errWant := errs[0].Error(), "view.title.text: must be at most 24 characters, has 28"
+	if errGot != errWant {
+		t.Errorf("Unexpected error, got: %v, want: %v", errGot, errWant)
+	}
+
+	pathGot, pathWant := errs[1].Path, "view.blocks[0].elements[0].action_id"
+	if pathGot != pathWant {
+		t.Errorf("Unexpected path, got: %v, want: %v", pathGot, pathWant)
+	}
+}
+
+func TestBuildLimits(t *testing.T) {
+	t.Parallel()
+
+	_, err := blocks.Build(blocks.Actions(blocks.Button("ok", strings.Repeat("a", 76))))
+
+	errGot, errWant := err.Error(), "blocks[0].elements[0].text.text: must be at most 75 characters, has 76"
+	if errGot != errWant {
+		t.Errorf("Unexpected error, got: %v, want: %v", errGot, errWant)
+	}
+}
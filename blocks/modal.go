@@ -0,0 +1,136 @@
+package blocks
+
+import (
+	"github.com/slack-go/slack"
+)
+
+// A modal view builder
+type ModalBuilder struct {
+	view   slack.ModalViewRequest
+	blocks []Block
+}
+
+// Creates a modal view with a title.
+func Modal(title string) *ModalBuilder {
+	return &ModalBuilder{view: slack.ModalViewRequest{
+		Type:  slack.VTModal,
+		Title: PlainText(title),
+	}}
+}
+
+// Sets the callback ID, which routes submissions to a view
+// submission handler.
+func (b *ModalBuilder) CallbackID(callbackID string) *ModalBuilder {
+	b.view.CallbackID = callbackID
+	return b
+}
+
+// Adds blocks to the view.
+func (b *ModalBuilder) Blocks(blocks ...Block) *ModalBuilder {
+	b.blocks = append(b.blocks, blocks...)
+	return b
+}
+
+// Sets the text of the submit button. Required for views with
+// input blocks.
+func (b *ModalBuilder) Submit(text string) *ModalBuilder {
+	b.view.Submit = PlainText(text)
+	return b
+}
+
+// Sets the text of the close button.
+func (b *ModalBuilder) Close(text string) *ModalBuilder {
+	b.view.Close = PlainText(text)
+	return b
+}
+
+// Sets the private metadata sent with the view's submissions.
+func (b *ModalBuilder) PrivateMetadata(metadata string) *ModalBuilder {
+	b.view.PrivateMetadata = metadata
+	return b
+}
+
+// Sets the external ID of the view.
+func (b *ModalBuilder) ExternalID(externalID string) *ModalBuilder {
+	b.view.ExternalID = externalID
+	return b
+}
+
+// Sends a view_closed request when the user closes the view.
+func (b *ModalBuilder) NotifyOnClose() *ModalBuilder {
+	b.view.NotifyOnClose = true
+	return b
+}
+
+// Closes the whole modal stack when the view is closed.
+func (b *ModalBuilder) ClearOnClose() *ModalBuilder {
+	b.view.ClearOnClose = true
+	return b
+}
+
+// Builds the view, e.g. for Client().OpenView.
+//
+// Returns an error listing every missing or invalid field, and every
+// field longer than its Block Kit limit.
+func (b *ModalBuilder) Build() (slack.ModalViewRequest, error) {
+	c := collector{path: "view"}
+	view := b.view
+	c.require("title", hasText(view.Title))
+
+	blocks, err := Build(b.blocks...)
+	c.merge(err)
+	hasInput := false
+	for _, block := range b.blocks {
+		if _, ok := block.(*InputBuilder); ok {
+			hasInput = true
+		}
+	}
+	if hasInput && !hasText(view.Submit) {
+		c.add("submit", "is required for views with input blocks")
+	}
+
+	if err := c.err(); err != nil {
+		return slack.ModalViewRequest{}, err
+	}
+	view.Blocks = slack.Blocks{BlockSet: blocks}
+	if err := ValidateView(view); err != nil {
+		return slack.ModalViewRequest{}, err
+	}
+	return view, nil
+}
+
+// Builds the view for a response action, i.e. ViewResponseAction.View.
+func (b *ModalBuilder) View() (*slack.View, error) {
+	view, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return ResponseView(view), nil
+}
+
+// Converts a view request to a view for a response action, i.e.
+// ViewResponseAction.View.
+func ResponseView(view slack.ModalViewRequest) *slack.View {
+	return &slack.View{
+		Type:            view.Type,
+		Title:           view.Title,
+		Blocks:          view.Blocks,
+		Close:           view.Close,
+		Submit:          view.Submit,
+		PrivateMetadata: view.PrivateMetadata,
+		CallbackID:      view.CallbackID,
+		ClearOnClose:    view.ClearOnClose,
+		NotifyOnClose:   view.NotifyOnClose,
+		ExternalID:      view.ExternalID,
+	}
+}
+
+// Builds the view like Build, but panics if a field is missing or
+// invalid.
+func (b *ModalBuilder) MustBuild() slack.ModalViewRequest {
+	view, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return view
+}
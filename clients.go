@@ -0,0 +1,151 @@
+package slap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// The default time a workspace's bot client is cached for.
+const defaultClientCacheTTL = 5 * time.Minute
+
+// Slack API errors meaning a token can no longer be used.
+var revokedTokenErrors = map[string]bool{
+	"invalid_auth":     true,
+	"token_revoked":    true,
+	"token_expired":    true,
+	"account_inactive": true,
+}
+
+// A cache of bot clients by installation.
+type clientCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]clientCacheEntry
+}
+
+type clientCacheEntry struct {
+	client    *slack.Client
+	expiresAt time.Time
+}
+
+func newClientCache(ttl time.Duration) *clientCache {
+	return &clientCache{
+		ttl:     ttl,
+		entries: make(map[string]clientCacheEntry),
+	}
+}
+
+func (c *clientCache) get(key string, now time.Time) (*slack.Client, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || now.After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.client, true
+}
+
+func (c *clientCache) set(key string, client *slack.Client, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = clientCacheEntry{client: client, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *clientCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Returns the bot client for a request's installation, resolving
+// the bot token if there is no cached client.
+//
+// If the token cannot be resolved, the client fails every API call.
+func (app *Application) botClient(ctx AuthorizeContext) *slack.Client {
+	key := ctx.installationQuery().key()
+	now := time.Now()
+	if client, ok := app.clients.get(key, now); ok {
+		return client
+	}
+
+	token, err := app.botToken(ctx)
+	if err != nil {
+		app.logger.Error("Could not get bot token", "teamID", ctx.TeamID, "enterpriseID", ctx.EnterpriseID, "error", err.Error())
+		return slack.New("", slack.OptionHTTPClient(failingHTTPClient{err: err}))
+	}
+
+	client := app.newClient(token, revokingHTTPClient{
+		client: app.httpClient,
+		revoke: func() {
+			app.logger.Warn("Bot token was rejected by Slack", "teamID", ctx.TeamID, "enterpriseID", ctx.EnterpriseID)
+			app.clients.invalidate(key)
+		},
+	})
+	app.clients.set(key, client, now)
+	return client
+}
+
+// The HTTP client interface used by slack.OptionHTTPClient.
+type httpDoer interface {
+	Do(r *http.Request) (*http.Response, error)
+}
+
+// Creates a Slack client with the API URL and options in Config.
+//
+// The HTTP client is set last, so a slack.OptionHTTPClient in
+// ClientOptions can't replace the client that detects revoked tokens.
+func (app *Application) newClient(token string, httpClient httpDoer) *slack.Client {
+	options := append([]slack.Option{slack.OptionAPIURL(app.apiURL)}, app.clientOptions...)
+	options = append(options, slack.OptionHTTPClient(httpClient))
+	return slack.New(token, options...)
+}
+
+// An HTTP client that evicts a cached Slack client when Slack
+// responds that its token has been revoked.
+type revokingHTTPClient struct {
+	client *http.Client
+	revoke func()
+}
+
+func (c revokingHTTPClient) Do(r *http.Request) (*http.Response, error) {
+	res, err := c.client.Do(r)
+	if err != nil || !strings.Contains(res.Header.Get("content-type"), "json") {
+		return res, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	var slackRes slack.SlackResponse
+	if json.Unmarshal(body, &slackRes) == nil && revokedTokenErrors[slackRes.Error] {
+		c.revoke()
+	}
+	return res, nil
+}
+
+// An HTTP client that fails every request.
+type failingHTTPClient struct {
+	err error
+}
+
+func (c failingHTTPClient) Do(*http.Request) (*http.Response, error) {
+	return nil, c.err
+}
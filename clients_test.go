@@ -0,0 +1,182 @@
+package slap_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+	"github.com/slack-go/slack"
+)
+
+func createClientCacheTestApp(apiURL string, resolves *atomic.Int32) (*slap.Application, *http.ServeMux) {
+	router := http.NewServeMux()
+	return slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotTokenResolver: func(ctx slap.AuthorizeContext) (string, error) {
+			resolves.Add(1)
+			return "xoxb-test", nil
+		},
+		ClientOptions: []slack.Option{slack.OptionAPIURL(apiURL + "/")},
+	}), router
+}
+
+func sendTestCommand(router *http.ServeMux) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/commands", bytes.NewReader(testCommandBody()))
+	r.Header.Add("content-type", "application/x-www-form-urlencoded")
+	addSignatureHeaders(r)
+	router.ServeHTTP(w, r)
+}
+
+func TestClientLazyTokenResolution(t *testing.T) {
+	t.Parallel()
+
+	var resolves atomic.Int32
+	app, router := createClientCacheTestApp("http://localhost", &resolves)
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.Ack()
+		return nil
+	})
+
+	sendTestCommand(router)
+
+	resolvesGot, resolvesWant := resolves.Load(), int32(0)
+	if resolvesGot != resolvesWant {
+		t.Errorf("Unexpected token resolutions, got: %v, want: %v", resolvesGot, resolvesWant)
+	}
+}
+
+func TestClientCached(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	defer server.Close()
+
+	var resolves atomic.Int32
+	app, router := createClientCacheTestApp(server.URL, &resolves)
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		_, err := req.Client().AuthTest()
+		if err != nil {
+			return err
+		}
+		req.Ack()
+		return nil
+	})
+
+	sendTestCommand(router)
+	sendTestCommand(router)
+
+	resolvesGot, resolvesWant := resolves.Load(), int32(1)
+	if resolvesGot != resolvesWant {
+		t.Errorf("Unexpected token resolutions, got: %v, want: %v", resolvesGot, resolvesWant)
+	}
+}
+
+func TestClientInvalidatedOnRevokedToken(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "token_revoked"})
+	}))
+	defer server.Close()
+
+	var resolves atomic.Int32
+	app, router := createClientCacheTestApp(server.URL, &resolves)
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.Client().AuthTest()
+		req.Ack()
+		return nil
+	})
+
+	sendTestCommand(router)
+	sendTestCommand(router)
+
+	resolvesGot, resolvesWant := resolves.Load(), int32(2)
+	if resolvesGot != resolvesWant {
+		t.Errorf("Unexpected token resolutions, got: %v, want: %v", resolvesGot, resolvesWant)
+	}
+}
+
+func TestClientAPIURL(t *testing.T) {
+	t.Parallel()
+
+	paths := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		w.Header().Set("content-type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"ok": true, "channel": "C0123456", "ts": "1.0"})
+	}))
+	defer server.Close()
+
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "xoxb-test", nil
+		},
+		APIURL: server.URL + "/api",
+	})
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		_, _, err := req.Client().PostMessage(req.Payload.ChannelID, slack.MsgOptionText("Hello", false))
+		if err != nil {
+			return err
+		}
+		req.Ack()
+		return nil
+	})
+
+	sendTestCommand(router)
+
+	pathGot, pathWant := <-paths, "/api/chat.postMessage"
+	if pathGot != pathWant {
+		t.Errorf("Unexpected API path, got: %v, want: %v", pathGot, pathWant)
+	}
+}
+
+func TestClientInvalidatedWithHTTPClientOption(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "token_revoked"})
+	}))
+	defer server.Close()
+
+	var resolves atomic.Int32
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotTokenResolver: func(ctx slap.AuthorizeContext) (string, error) {
+			resolves.Add(1)
+			return "xoxb-test", nil
+		},
+		ClientOptions: []slack.Option{
+			slack.OptionAPIURL(server.URL + "/"),
+			slack.OptionHTTPClient(http.DefaultClient),
+		},
+	})
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.Client().AuthTest()
+		req.Ack()
+		return nil
+	})
+
+	sendTestCommand(router)
+	sendTestCommand(router)
+
+	resolvesGot, resolvesWant := resolves.Load(), int32(2)
+	if resolvesGot != resolvesWant {
+		t.Errorf("Unexpected token resolutions, got: %v, want: %v", resolvesGot, resolvesWant)
+	}
+}
@@ -0,0 +1,62 @@
+// Replays a recording made by a slap.Recorder against a running
+// Slap Application and reports responses that differ.
+//
+// Usage:
+//
+//	slap-replay -url http://localhost:4000 recording.jsonl
+//
+// The signing secret is read from -secret or $SIGNING_SECRET.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jacob-ian/slap/replay"
+)
+
+func main() {
+	url := flag.String("url", "http://localhost:4000", "The base URL of the Slap Application")
+	secret := flag.String("secret", os.Getenv("SIGNING_SECRET"), "The Slack signing secret used by the application")
+	flag.Parse()
+
+	if flag.NArg() != 1 || *secret == "" {
+		fmt.Fprintln(os.Stderr, "Usage: slap-replay [-url URL] [-secret SECRET] RECORDING")
+		os.Exit(2)
+	}
+
+	file, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	records, err := replay.ReadRecords(file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	results, err := replay.Run(replay.URL(*url, *secret, nil), records)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	mismatches := 0
+	for i, result := range results {
+		if result.Matches() {
+			fmt.Printf("ok   %v %v\n", i+1, result.Record.Path)
+			continue
+		}
+		mismatches++
+		fmt.Printf("FAIL %v %v\n%v\n", i+1, result.Record.Path, result.Diff)
+	}
+
+	fmt.Printf("%v of %v requests matched\n", len(results)-mismatches, len(results))
+	if mismatches > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,633 @@
+package slap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The type of a command argument or flag value
+type ArgType int
+
+// The supported ArgType values
+const (
+	StringArg ArgType = iota
+	IntArg
+	FloatArg
+	BoolArg
+	// A user mention, e.g. <@U0123456|alice>
+	UserArg
+	// A channel mention, e.g. <#C0123456|general>
+	ChannelArg
+	// A user group mention, e.g. <!subteam^S0123456|@admins>
+	UserGroupArg
+	// A link, e.g. <https://example.com>
+	LinkArg
+)
+
+func (t ArgType) String() string {
+	switch t {
+	case IntArg:
+		return "number"
+	case FloatArg:
+		return "decimal"
+	case BoolArg:
+		return "true|false"
+	case UserArg:
+		return "@user"
+	case ChannelArg:
+		return "#channel"
+	case UserGroupArg:
+		return "@group"
+	case LinkArg:
+		return "link"
+	default:
+		return "text"
+	}
+}
+
+// A positional argument of a slash command
+type CommandArg struct {
+	// The name of the argument, shown in help text
+	Name string
+	// A description of the argument, shown in help text
+	Description string
+	// The type the argument's value is parsed into. Defaults to StringArg.
+	Type ArgType
+	// Whether the argument must be provided
+	Required bool
+	// Whether the argument takes all remaining values. Only allowed on the last argument.
+	Variadic bool
+	// The allowed values of the argument, if restricted
+	Choices []string
+}
+
+// A named flag of a slash command, used as "--name value", "--name=value"
+// or "--name" for BoolArg flags
+type CommandFlag struct {
+	// The name of the flag, without the leading "--"
+	Name string
+	// A description of the flag, shown in help text
+	Description string
+	// The type the flag's value is parsed into. Defaults to StringArg.
+	Type ArgType
+	// The value used when the flag isn't provided
+	Default string
+	// Whether the flag must be provided
+	Required bool
+	// The allowed values of the flag, if restricted
+	Choices []string
+}
+
+// A function to handle a slash command with parsed arguments
+type CommandRouteHandler func(req *CommandRequest, args CommandArgs) error
+
+// A subcommand of a slash command, e.g. "rollback" in "/deploy rollback api"
+type Subcommand struct {
+	// The name of the subcommand
+	Name string
+	// A description of the subcommand, shown in help text
+	Description string
+	// The positional arguments of the subcommand
+	Args []CommandArg
+	// The flags of the subcommand
+	Flags []CommandFlag
+	// The function to handle the subcommand
+	Handler CommandRouteHandler
+}
+
+// Routes a slash command's text to subcommands, parsing their
+// arguments and flags.
+//
+// Help text is sent as an ephemeral response for "help", "--help"
+// and an empty command, and parse errors are sent to the user
+// as an ephemeral response with the subcommand's usage.
+type CommandRouter struct {
+	command     string
+	description string
+	root        *Subcommand
+	subcommands []Subcommand
+}
+
+// Creates a CommandRouter for a slash command, e.g. "/deploy".
+func NewCommandRouter(command string, description string) *CommandRouter {
+	return &CommandRouter{
+		command:     command,
+		description: description,
+	}
+}
+
+// Registers a subcommand.
+//
+// Panics if the subcommand is invalid or has already been registered.
+func (r *CommandRouter) Subcommand(sub Subcommand) {
+	if sub.Name == "" || sub.Name == "help" || strings.HasPrefix(sub.Name, "-") {
+		panic(fmt.Sprintf("Invalid subcommand name %q for %v", sub.Name, r.command))
+	}
+	for _, existing := range r.subcommands {
+		if existing.Name == sub.Name {
+			panic(fmt.Sprintf("Subcommand %v of %v has already been registered", sub.Name, r.command))
+		}
+	}
+	sub.validate(r.command)
+	r.subcommands = append(r.subcommands, sub)
+}
+
+// Registers the handler used when the text doesn't start with a
+// subcommand. The Name of the Subcommand is ignored.
+//
+// Without a root handler, an empty command shows the help text
+// and unknown subcommands are reported as errors.
+func (r *CommandRouter) Root(sub Subcommand) {
+	sub.Name = ""
+	sub.validate(r.command)
+	r.root = &sub
+}
+
+func (sub Subcommand) validate(command string) {
+	if sub.Handler == nil {
+		panic(fmt.Sprintf("Missing handler for %v %v", command, sub.Name))
+	}
+	for i, arg := range sub.Args {
+		if arg.Variadic && i != len(sub.Args)-1 {
+			panic(fmt.Sprintf("Only the last argument of %v %v can be variadic", command, sub.Name))
+		}
+	}
+}
+
+// Registers a CommandRouter as the handler of its slash command.
+//
+// Panics if the command has already been registered.
+func (app *Application) RegisterCommandRouter(router *CommandRouter) {
+	app.RegisterCommand(router.command, router.Handle)
+}
+
+// Handles a slash command request. Can be registered directly with
+// RegisterCommand.
+func (r *CommandRouter) Handle(req *CommandRequest) error {
+	tokens, err := splitCommandText(req.Payload.Text)
+	if err != nil {
+		req.AckWithAction(ephemeralAction(fmt.Sprintf("%v\n\n%v", err.Error(), r.help())))
+		return nil
+	}
+
+	if len(tokens) > 0 && (tokens[0] == "help" || tokens[0] == "--help") {
+		if len(tokens) > 1 {
+			if sub, ok := r.find(tokens[1]); ok {
+				req.AckWithAction(ephemeralAction(r.subcommandHelp(sub)))
+				return nil
+			}
+		}
+		req.AckWithAction(ephemeralAction(r.help()))
+		return nil
+	}
+
+	var sub *Subcommand
+	if len(tokens) > 0 {
+		if s, ok := r.find(tokens[0]); ok {
+			sub = s
+			tokens = tokens[1:]
+		}
+	}
+	if sub == nil {
+		if r.root == nil {
+			if len(tokens) == 0 {
+				req.AckWithAction(ephemeralAction(r.help()))
+				return nil
+			}
+			message := fmt.Sprintf("Unknown subcommand `%v`.\n\n%v", tokens[0], r.help())
+			req.AckWithAction(ephemeralAction(message))
+			return nil
+		}
+		sub = r.root
+	}
+
+	for _, token := range tokens {
+		if token == "--help" {
+			req.AckWithAction(ephemeralAction(r.subcommandHelp(sub)))
+			return nil
+		}
+	}
+
+	args, err := sub.parse(tokens)
+	if err != nil {
+		message := fmt.Sprintf("%v\n\n%v", err.Error(), r.subcommandHelp(sub))
+		req.AckWithAction(ephemeralAction(message))
+		return nil
+	}
+	return sub.Handler(req, args)
+}
+
+func (r *CommandRouter) find(name string) (*Subcommand, bool) {
+	for i := range r.subcommands {
+		if r.subcommands[i].Name == name {
+			return &r.subcommands[i], true
+		}
+	}
+	return nil, false
+}
+
+func ephemeralAction(text string) CommandResponseAction {
+	return CommandResponseAction{
+		ResponseType: RespondEphemeral,
+		Text:         text,
+	}
+}
+
+// The parsed arguments and flags of a slash command
+type CommandArgs struct {
+	values map[string][]any
+}
+
+// Whether an argument or flag was provided or has a default.
+func (a CommandArgs) Has(name string) bool {
+	return len(a.values[name]) > 0
+}
+
+// The value of a StringArg argument or flag, unescaped, e.g. "a & b"
+// for "a &amp; b".
+func (a CommandArgs) String(name string) string {
+	v, _ := a.first(name).(string)
+	return v
+}
+
+// The value of an IntArg argument or flag.
+func (a CommandArgs) Int(name string) int {
+	v, _ := a.first(name).(int)
+	return v
+}
+
+// The value of a FloatArg argument or flag.
+func (a CommandArgs) Float(name string) float64 {
+	v, _ := a.first(name).(float64)
+	return v
+}
+
+// The value of a BoolArg argument or flag.
+func (a CommandArgs) Bool(name string) bool {
+	v, _ := a.first(name).(bool)
+	return v
+}
+
+// The mentioned user, channel or user group, or link of a UserArg,
+// ChannelArg, UserGroupArg or LinkArg argument or flag.
+func (a CommandArgs) Entity(name string) Entity {
+	v, _ := a.first(name).(Entity)
+	return v
+}
+
+// The ID of the user mentioned in a UserArg argument or flag.
+func (a CommandArgs) User(name string) string {
+	return a.Entity(name).ID
+}
+
+// The ID of the channel mentioned in a ChannelArg argument or flag.
+func (a CommandArgs) Channel(name string) string {
+	return a.Entity(name).ID
+}
+
+// The ID of the user group mentioned in a UserGroupArg argument or flag.
+func (a CommandArgs) UserGroup(name string) string {
+	return a.Entity(name).ID
+}
+
+// The URL of a LinkArg argument or flag.
+func (a CommandArgs) Link(name string) string {
+	return a.Entity(name).URL
+}
+
+// The values of a variadic StringArg argument.
+func (a CommandArgs) Strings(name string) []string {
+	values := []string{}
+	for _, v := range a.values[name] {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// The values of a variadic argument, typed as per its ArgType.
+func (a CommandArgs) Values(name string) []any {
+	return a.values[name]
+}
+
+func (a CommandArgs) first(name string) any {
+	values := a.values[name]
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+func (sub *Subcommand) parse(tokens []string) (CommandArgs, error) {
+	args := CommandArgs{values: make(map[string][]any)}
+	positional := []string{}
+	provided := make(map[string]bool)
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token == "--" {
+			positional = append(positional, tokens[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(token, "--") {
+			positional = append(positional, token)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(token, "--"), "=")
+		flag, ok := sub.flag(name)
+		if !ok {
+			return args, fmt.Errorf("Unknown flag `--%v`.", name)
+		}
+		if !hasValue {
+			if flag.Type == BoolArg {
+				value = "true"
+			} else if i+1 < len(tokens) {
+				i++
+				value = tokens[i]
+			} else {
+				return args, fmt.Errorf("Missing value for `--%v`.", name)
+			}
+		}
+		parsed, err := parseInputValue(flag.Type, value, flag.Choices)
+		if err != nil {
+			return args, fmt.Errorf("Invalid value for `--%v`: %v", name, err.Error())
+		}
+		args.values[name] = []any{parsed}
+		provided[name] = true
+	}
+
+	for _, flag := range sub.Flags {
+		if provided[flag.Name] {
+			continue
+		}
+		if flag.Required {
+			return args, fmt.Errorf("Missing required flag `--%v`.", flag.Name)
+		}
+		if flag.Default == "" {
+			continue
+		}
+		parsed, err := parseArgValue(flag.Type, flag.Default, nil)
+		if err != nil {
+			return args, fmt.Errorf("Invalid default for `--%v`: %v", flag.Name, err.Error())
+		}
+		args.values[flag.Name] = []any{parsed}
+	}
+
+	for _, arg := range sub.Args {
+		if len(positional) == 0 {
+			if arg.Required {
+				return args, fmt.Errorf("Missing required argument `%v`.", arg.Name)
+			}
+			continue
+		}
+		values := positional[:1]
+		if arg.Variadic {
+			values = positional
+		}
+		for _, value := range values {
+			parsed, err := parseInputValue(arg.Type, value, arg.Choices)
+			if err != nil {
+				return args, fmt.Errorf("Invalid value for `%v`: %v", arg.Name, err.Error())
+			}
+			args.values[arg.Name] = append(args.values[arg.Name], parsed)
+		}
+		positional = positional[len(values):]
+	}
+
+	if len(positional) > 0 {
+		return args, fmt.Errorf("Unexpected argument `%v`.", positional[0])
+	}
+	return args, nil
+}
+
+func (sub *Subcommand) flag(name string) (CommandFlag, bool) {
+	for _, flag := range sub.Flags {
+		if flag.Name == name {
+			return flag, true
+		}
+	}
+	return CommandFlag{}, false
+}
+
+// Parses a value from the command's text, unescaping the "&", "<"
+// and ">" that Slack escapes, except in mentions and links.
+func parseInputValue(t ArgType, value string, choices []string) (any, error) {
+	switch t {
+	case UserArg, ChannelArg, UserGroupArg, LinkArg:
+	default:
+		value = unescapeText(value)
+	}
+	return parseArgValue(t, value, choices)
+}
+
+func parseArgValue(t ArgType, value string, choices []string) (any, error) {
+	if len(choices) > 0 && !contains(choices, value) {
+		return nil, fmt.Errorf("expected one of %v.", strings.Join(choices, ", "))
+	}
+	switch t {
+	case IntArg:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("`%v` is not a whole number.", value)
+		}
+		return v, nil
+	case FloatArg:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("`%v` is not a number.", value)
+		}
+		return v, nil
+	case BoolArg:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("`%v` is not true or false.", value)
+		}
+		return v, nil
+	case UserArg:
+		return parseEntityArg(value, UserEntity)
+	case ChannelArg:
+		return parseEntityArg(value, ChannelEntity)
+	case UserGroupArg:
+		return parseEntityArg(value, UserGroupEntity)
+	case LinkArg:
+		return parseEntityArg(value, LinkEntity)
+	default:
+		return value, nil
+	}
+}
+
+func parseEntityArg(value string, t EntityType) (any, error) {
+	entity, ok := parseEntityToken(value)
+	if !ok || entity.Type != t {
+		return nil, fmt.Errorf("`%v` is not a %v.", unescapeText(value), t.String())
+	}
+	return entity, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Splits command text into whitespace separated tokens, keeping
+// quoted text and escaped entities together. Slack's "smart" quotes
+// are also supported.
+//
+// Quotes only start quoted text at the start of a token or after the
+// "=" of a flag, so apostrophes like "don't" are kept as they are.
+func splitCommandText(text string) ([]string, error) {
+	tokens := []string{}
+	var current strings.Builder
+	inToken := false
+	inEntity := false
+	var quote rune
+	var previous rune
+
+	for _, c := range text {
+		quoteStart := !inToken || previous == '='
+		previous = c
+		switch {
+		case inEntity:
+			current.WriteRune(c)
+			inEntity = c != '>'
+		case quote != 0:
+			if c == quote {
+				quote = 0
+				continue
+			}
+			current.WriteRune(c)
+		case c == '<':
+			current.WriteRune(c)
+			inEntity = true
+			inToken = true
+		case quoteStart && (c == '"' || c == '\''):
+			quote = c
+			inToken = true
+		case quoteStart && c == '“':
+			quote = '”'
+			inToken = true
+		case quoteStart && c == '‘':
+			quote = '’'
+			inToken = true
+		case unicode.IsSpace(c):
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(c)
+			inToken = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("Missing closing quote.")
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+func (r *CommandRouter) help() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*%v*", r.command))
+	if r.description != "" {
+		b.WriteString(" - " + r.description)
+	}
+	b.WriteString("\n\n*Usage*\n")
+	if r.root != nil {
+		b.WriteString(fmt.Sprintf("`%v`\n", r.usage(r.root)))
+	}
+	if len(r.subcommands) > 0 {
+		b.WriteString(fmt.Sprintf("`%v <subcommand>`\n\n*Subcommands*\n", r.command))
+		for i := range r.subcommands {
+			sub := &r.subcommands[i]
+			b.WriteString(fmt.Sprintf("• `%v`", strings.TrimPrefix(r.usage(sub), r.command+" ")))
+			if sub.Description != "" {
+				b.WriteString(" " + sub.Description)
+			}
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("• `help [subcommand]` Show this help")
+	return b.String()
+}
+
+func (r *CommandRouter) subcommandHelp(sub *Subcommand) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("*Usage*\n`%v`", r.usage(sub)))
+	if sub.Description != "" {
+		b.WriteString("\n" + sub.Description)
+	}
+	if len(sub.Args) > 0 {
+		b.WriteString("\n\n*Arguments*")
+		for _, arg := range sub.Args {
+			b.WriteString(fmt.Sprintf("\n• `%v` (%v)", arg.Name, describeType(arg.Type, arg.Choices)))
+			if arg.Description != "" {
+				b.WriteString(" " + arg.Description)
+			}
+		}
+	}
+	if len(sub.Flags) > 0 {
+		b.WriteString("\n\n*Flags*")
+		for _, flag := range sub.Flags {
+			b.WriteString(fmt.Sprintf("\n• `--%v` (%v", flag.Name, describeType(flag.Type, flag.Choices)))
+			if flag.Default != "" {
+				b.WriteString(", default: " + flag.Default)
+			}
+			b.WriteString(")")
+			if flag.Description != "" {
+				b.WriteString(" " + flag.Description)
+			}
+		}
+	}
+	return b.String()
+}
+
+func (r *CommandRouter) usage(sub *Subcommand) string {
+	parts := []string{r.command}
+	if sub.Name != "" {
+		parts = append(parts, sub.Name)
+	}
+	for _, arg := range sub.Args {
+		name := arg.Name
+		if arg.Variadic {
+			name += "..."
+		}
+		if arg.Required {
+			parts = append(parts, "<"+name+">")
+		} else {
+			parts = append(parts, "["+name+"]")
+		}
+	}
+	for _, flag := range sub.Flags {
+		usage := "--" + flag.Name
+		if flag.Type != BoolArg {
+			usage += " <" + flag.Name + ">"
+		}
+		if flag.Required {
+			parts = append(parts, usage)
+		} else {
+			parts = append(parts, "["+usage+"]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func describeType(t ArgType, choices []string) string {
+	if len(choices) > 0 {
+		return strings.Join(choices, "|")
+	}
+	return t.String()
+}
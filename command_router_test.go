@@ -0,0 +1,282 @@
+package slap_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+)
+
+func createDeployRouter(got *slap.CommandArgs) *slap.CommandRouter {
+	router := slap.NewCommandRouter("/deploy", "Deploy services")
+	router.Subcommand(slap.Subcommand{
+		Name:        "rollback",
+		Description: "Roll back a service",
+		Args: []slap.CommandArg{
+			{Name: "service", Required: true},
+			{Name: "version", Type: slap.IntArg},
+		},
+		Flags: []slap.CommandFlag{
+			{Name: "env", Default: "production", Choices: []string{"staging", "production"}},
+			{Name: "force", Type: slap.BoolArg},
+		},
+		Handler: func(req *slap.CommandRequest, args slap.CommandArgs) error {
+			*got = args
+			req.Ack()
+			return nil
+		},
+	})
+	return router
+}
+
+func sendDeploy(t *testing.T, text string) (*slaptest.Response, slap.CommandArgs) {
+	var args slap.CommandArgs
+	app, router := createTestApp()
+	app.RegisterCommandRouter(createDeployRouter(&args))
+	tester := slaptest.New(router, "signing-secret")
+	res := tester.Command(slaptest.Command{Command: "/deploy", Text: text})
+	return res, args
+}
+
+func TestCommandRouterSubcommand(t *testing.T) {
+	t.Parallel()
+
+	res, args := sendDeploy(t, `rollback "api gateway" 42 --force --env=staging`)
+	if res.StatusCode != 200 || res.Text() != "" {
+		t.Fatalf("Unexpected response, got: %v %v", res.StatusCode, res.Text())
+	}
+
+	serviceGot, serviceWant := args.String("service"), "api gateway"
+	if serviceGot != serviceWant {
+		t.Errorf("Unexpected service, got: %v, want: %v", serviceGot, serviceWant)
+	}
+
+	versionGot, versionWant := args.Int("version"), 42
+	if versionGot != versionWant {
+		t.Errorf("Unexpected version, got: %v, want: %v", versionGot, versionWant)
+	}
+
+	envGot, envWant := args.String("env"), "staging"
+	if envGot != envWant {
+		t.Errorf("Unexpected env, got: %v, want: %v", envGot, envWant)
+	}
+
+	if !args.Bool("force") {
+		t.Errorf("Expected force to be true")
+	}
+}
+
+func TestCommandRouterDefaults(t *testing.T) {
+	t.Parallel()
+
+	_, args := sendDeploy(t, "rollback api")
+
+	envGot, envWant := args.String("env"), "production"
+	if envGot != envWant {
+		t.Errorf("Unexpected env, got: %v, want: %v", envGot, envWant)
+	}
+
+	if args.Has("version") || args.Bool("force") {
+		t.Errorf("Unexpected optional values: %v %v", args.Int("version"), args.Bool("force"))
+	}
+}
+
+func TestCommandRouterApostrophe(t *testing.T) {
+	t.Parallel()
+
+	res, args := sendDeploy(t, `rollback don't --env='staging'`)
+	if res.StatusCode != 200 || res.Text() != "" {
+		t.Fatalf("Unexpected response, got: %v %v", res.StatusCode, res.Text())
+	}
+
+	serviceGot, serviceWant := args.String("service"), "don't"
+	if serviceGot != serviceWant {
+		t.Errorf("Unexpected service, got: %v, want: %v", serviceGot, serviceWant)
+	}
+
+	envGot, envWant := args.String("env"), "staging"
+	if envGot != envWant {
+		t.Errorf("Unexpected env, got: %v, want: %v", envGot, envWant)
+	}
+}
+
+func TestCommandRouterUnescapesStrings(t *testing.T) {
+	t.Parallel()
+
+	var got slap.CommandArgs
+	app, router := createTestApp()
+	commands := slap.NewCommandRouter("/note", "Take notes")
+	commands.Subcommand(slap.Subcommand{
+		Name:  "add",
+		Args:  []slap.CommandArg{{Name: "words", Variadic: true}},
+		Flags: []slap.CommandFlag{{Name: "tag", Choices: []string{"a&b", "c"}}},
+		Handler: func(req *slap.CommandRequest, args slap.CommandArgs) error {
+			got = args
+			req.Ack()
+			return nil
+		},
+	})
+	app.RegisterCommandRouter(commands)
+	tester := slaptest.New(router, "signing-secret")
+
+	res := tester.Command(slaptest.Command{Command: "/note", Text: "add a &amp; b &lt;x&gt; --tag a&amp;b"})
+	if res.StatusCode != 200 || res.Text() != "" {
+		t.Fatalf("Unexpected response, got: %v %v", res.StatusCode, res.Text())
+	}
+
+	wordsGot, wordsWant := strings.Join(got.Strings("words"), " "), "a & b <x>"
+	if wordsGot != wordsWant {
+		t.Errorf("Unexpected words, got: %v, want: %v", wordsGot, wordsWant)
+	}
+
+	tagGot, tagWant := got.String("tag"), "a&b"
+	if tagGot != tagWant {
+		t.Errorf("Unexpected tag, got: %v, want: %v", tagGot, tagWant)
+	}
+}
+
+func TestCommandRouterParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"rollback":                 "Missing required argument `service`.",
+		"rollback api one":         "Invalid value for `version`: `one` is not a whole number.",
+		"rollback api --env dev":   "Invalid value for `--env`: expected one of staging, production.",
+		"rollback api --region us": "Unknown flag `--region`.",
+		"rollback api 1 2":         "Unexpected argument `2`.",
+		`rollback "api`:            "Missing closing quote.",
+		"restart api":              "Unknown subcommand `restart`.",
+	}
+
+	for text, want := range tests {
+		res, _ := sendDeploy(t, text)
+
+		var action slap.CommandResponseAction
+		if err := res.JSON(&action); err != nil {
+			t.Fatalf("Could not decode response for %q: %v", text, err.Error())
+		}
+
+		if action.ResponseType != slap.RespondEphemeral {
+			t.Errorf("Unexpected response type for %q, got: %v", text, action.ResponseType)
+		}
+		if !strings.HasPrefix(action.Text, want) {
+			t.Errorf("Unexpected error for %q, got: %v, want: %v", text, action.Text, want)
+		}
+		if !strings.Contains(action.Text, "*Usage*") {
+			t.Errorf("Expected usage in error for %q, got: %v", text, action.Text)
+		}
+	}
+}
+
+func TestCommandRouterHelp(t *testing.T) {
+	t.Parallel()
+
+	for _, text := range []string{"", "help", "--help"} {
+		res, _ := sendDeploy(t, text)
+
+		var action slap.CommandResponseAction
+		if err := res.JSON(&action); err != nil {
+			t.Fatalf("Could not decode response for %q: %v", text, err.Error())
+		}
+
+		want := "• `rollback <service> [version] [--env <env>] [--force]` Roll back a service"
+		if !strings.Contains(action.Text, want) {
+			t.Errorf("Unexpected help for %q, got: %v, want: %v", text, action.Text, want)
+		}
+	}
+
+	res, _ := sendDeploy(t, "rollback --help")
+	var action slap.CommandResponseAction
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	want := "• `--env` (staging|production, default: production)"
+	if !strings.Contains(action.Text, want) {
+		t.Errorf("Unexpected subcommand help, got: %v, want: %v", action.Text, want)
+	}
+}
+
+func TestCommandRouterRoot(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	app, router := createTestApp()
+	commands := slap.NewCommandRouter("/echo", "Echo text")
+	commands.Root(slap.Subcommand{
+		Args: []slap.CommandArg{{Name: "words", Variadic: true}},
+		Handler: func(req *slap.CommandRequest, args slap.CommandArgs) error {
+			got = args.Strings("words")
+			req.Ack()
+			return nil
+		},
+	})
+	app.RegisterCommandRouter(commands)
+
+	tester := slaptest.New(router, "signing-secret")
+	tester.Command(slaptest.Command{Command: "/echo", Text: "hello “big world”"})
+
+	gotText, wantText := strings.Join(got, ","), "hello,big world"
+	if gotText != wantText {
+		t.Errorf("Unexpected words, got: %v, want: %v", gotText, wantText)
+	}
+}
+
+func TestCommandRouterEntityArgs(t *testing.T) {
+	t.Parallel()
+
+	var got slap.CommandArgs
+	app, router := createTestApp()
+	commands := slap.NewCommandRouter("/invite", "Invite a user")
+	commands.Root(slap.Subcommand{
+		Args: []slap.CommandArg{
+			{Name: "user", Type: slap.UserArg, Required: true},
+			{Name: "channel", Type: slap.ChannelArg, Required: true},
+		},
+		Flags: []slap.CommandFlag{{Name: "docs", Type: slap.LinkArg}},
+		Handler: func(req *slap.CommandRequest, args slap.CommandArgs) error {
+			got = args
+			req.Ack()
+			return nil
+		},
+	})
+	app.RegisterCommandRouter(commands)
+	tester := slaptest.New(router, "signing-secret")
+
+	tester.Command(slaptest.Command{
+		Command: "/invite",
+		Text:    "<@U0654321|alice smith> <#C0654321|general> --docs <https://example.com|our docs>",
+	})
+
+	userGot, userWant := got.User("user"), "U0654321"
+	if userGot != userWant {
+		t.Errorf("Unexpected user, got: %v, want: %v", userGot, userWant)
+	}
+
+	labelGot, labelWant := got.Entity("user").Label, "alice smith"
+	if labelGot != labelWant {
+		t.Errorf("Unexpected user label, got: %v, want: %v", labelGot, labelWant)
+	}
+
+	channelGot, channelWant := got.Channel("channel"), "C0654321"
+	if channelGot != channelWant {
+		t.Errorf("Unexpected channel, got: %v, want: %v", channelGot, channelWant)
+	}
+
+	linkGot, linkWant := got.Link("docs"), "https://example.com"
+	if linkGot != linkWant {
+		t.Errorf("Unexpected link, got: %v, want: %v", linkGot, linkWant)
+	}
+
+	res := tester.Command(slaptest.Command{Command: "/invite", Text: "@alice <#C0654321>"})
+	var action slap.CommandResponseAction
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	errorWant := "Invalid value for `user`: `@alice` is not a user."
+	if !strings.HasPrefix(action.Text, errorWant) {
+		t.Errorf("Unexpected error, got: %v, want: %v", action.Text, errorWant)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/jacob-ian/slap/blocks"
 	"github.com/slack-go/slack"
 )
 
@@ -24,6 +25,8 @@ type CommandPayload struct {
 	EnterpriseID string
 	// The name of the enterprise this workspace belongs to if using Enterprise Grid..
 	EnterpriseName string
+	// Whether the app was installed to the entire Enterprise Grid.
+	IsEnterpriseInstall bool
 	// The ID of the channel the command was used in.
 	ChannelID string
 	// The name of the channel the command was used in.
@@ -82,6 +85,13 @@ func (req *CommandRequest) AckWithAction(action CommandResponseAction) {
 		return
 	}
 	req.ackCalled = true
+	if req.app.blockLimits != IgnoreBlockLimits {
+		err := req.checkBlockLimits("command response action", blocks.ValidateMessage(action.Text, action.Blocks))
+		if err != nil {
+			req.errChannel <- err
+			return
+		}
+	}
 	bytes, err := json.Marshal(action)
 	if err != nil {
 		req.Logger.Error("Could not encode command response action", "error", err.Error())
@@ -100,20 +110,21 @@ func (app *Application) handleCommand(w http.ResponseWriter, r *http.Request) {
 	}
 
 	payload := CommandPayload{
-		Token:          r.PostForm.Get("token"),
-		Command:        r.PostForm.Get("command"),
-		Text:           r.PostForm.Get("text"),
-		TeamID:         r.PostForm.Get("team_id"),
-		TeamDomain:     r.PostForm.Get("team_domain"),
-		EnterpriseID:   r.PostForm.Get("enterprise_id"),
-		EnterpriseName: r.PostForm.Get("enterprise_name"),
-		ChannelID:      r.PostForm.Get("channel_id"),
-		ChannelName:    r.PostForm.Get("channel_name"),
-		UserID:         r.PostForm.Get("user_id"),
-		UserName:       r.PostForm.Get("user_name"),
-		ResponseURL:    r.PostForm.Get("response_url"),
-		TriggerID:      r.PostForm.Get("trigger_id"),
-		APIAppID:       r.PostForm.Get("api_app_id"),
+		Token:               r.PostForm.Get("token"),
+		Command:             r.PostForm.Get("command"),
+		Text:                r.PostForm.Get("text"),
+		TeamID:              r.PostForm.Get("team_id"),
+		TeamDomain:          r.PostForm.Get("team_domain"),
+		EnterpriseID:        r.PostForm.Get("enterprise_id"),
+		EnterpriseName:      r.PostForm.Get("enterprise_name"),
+		IsEnterpriseInstall: r.PostForm.Get("is_enterprise_install") == "true",
+		ChannelID:           r.PostForm.Get("channel_id"),
+		ChannelName:         r.PostForm.Get("channel_name"),
+		UserID:              r.PostForm.Get("user_id"),
+		UserName:            r.PostForm.Get("user_name"),
+		ResponseURL:         r.PostForm.Get("response_url"),
+		TriggerID:           r.PostForm.Get("trigger_id"),
+		APIAppID:            r.PostForm.Get("api_app_id"),
 	}
 
 	if err = payload.validate(); err != nil {
@@ -128,13 +139,12 @@ func (app *Application) handleCommand(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	botToken, err := app.botToken(payload.TeamID)
-	if err != nil {
-		app.logger.Error("Could not get bot token", "teamID", payload.TeamID, "error", err.Error())
-		http.Error(w, "An error occurred", http.StatusInternalServerError)
-		return
+	authorize := AuthorizeContext{
+		EnterpriseID:        payload.EnterpriseID,
+		TeamID:              payload.TeamID,
+		IsEnterpriseInstall: payload.IsEnterpriseInstall,
+		UserID:              payload.UserID,
 	}
-
 	ackChan := make(chan []byte)
 	errChan := make(chan error)
 
@@ -142,24 +152,32 @@ func (app *Application) handleCommand(w http.ResponseWriter, r *http.Request) {
 		req := &CommandRequest{
 			Payload: payload,
 			baseRequest: baseRequest{
-				errChannel: errChan,
-				ackChannel: ackChan,
-				ackCalled:  false,
-				writer:     w,
-				Client:     slack.New(botToken),
-				Logger:     app.logger,
+				errChannel:  errChan,
+				ackChannel:  ackChan,
+				ackCalled:   false,
+				writer:      w,
+				Logger:      app.logger,
+				app:         app,
+				authorize:   authorize,
+				responseURL: newResponseURL(payload.ResponseURL),
+				context: RequestContext{
+					EnterpriseID:        payload.EnterpriseID,
+					TeamID:              payload.TeamID,
+					IsEnterpriseInstall: payload.IsEnterpriseInstall,
+					UserID:              payload.UserID,
+					ChannelID:           payload.ChannelID,
+					APIAppID:            payload.APIAppID,
+					TriggerID:           payload.TriggerID,
+					ResponseURL:         payload.ResponseURL,
+				},
 			},
 		}
 		err := handler(req)
 		if err == nil {
 			return
 		}
-		app.logger.Error("A command handler failed", "command", req.Payload.Command, "error", err.Error())
-		_, msgerr := req.Client.PostEphemeral(req.Payload.ChannelID, req.Payload.UserID, slack.MsgOptionText(app.errorMessage, false))
-		if msgerr != nil {
-			app.logger.Error("Unable to send error message to user", "user", req.Payload.UserID, "error", msgerr.Error())
-		}
-		errChan <- err
+		app.logger.Error("A command handler failed", "command", req.Payload.Command, "code", ErrorCode(err), "error", err.Error())
+		app.handleError(req, CommandRequestKind, &req.Payload, err)
 	}()
 
 	select {
@@ -0,0 +1,88 @@
+package slap
+
+import (
+	"github.com/slack-go/slack"
+)
+
+// The context of a request, with the same fields for every kind of
+// request. Fields are empty when Slack doesn't send them for the
+// kind of request, e.g. ChannelID for a view submission.
+type RequestContext struct {
+	// The Enterprise ID of the workspace if using Enterprise Grid
+	EnterpriseID string
+	// The Team ID of the workspace
+	TeamID string
+	// Whether the app was installed to the entire Enterprise Grid
+	IsEnterpriseInstall bool
+	// The ID of the user who made the request
+	UserID string
+	// The ID of the channel the request came from
+	ChannelID string
+	// The timestamp of the thread the request came from
+	ThreadTimestamp string
+	// Your Slack App's unique identifier
+	APIAppID string
+	// A short-lived ID that can be used to open modals
+	TriggerID string
+	// A temporary webhook URL that can be used with Respond
+	ResponseURL string
+	// The user's locale, e.g. "en-US", when the app has enabled
+	// include_locale. See Request.Locale.
+	Locale string
+}
+
+// The methods shared by every request type, for middleware and
+// helpers that handle any kind of request. It can also be implemented
+// by a fake request for testing them.
+type Request interface {
+	// The IDs and URLs shared by every kind of request
+	RequestContext() RequestContext
+	// Acknowledge Slack's request with Status 200
+	Ack()
+	// A Slack API client authorized as the app
+	Client() *slack.Client
+	// A Slack API client authorized as the user who made the request
+	UserClient() (*slack.Client, error)
+	// Post a message to the conversation the request came from
+	Say(options ...slack.MsgOption) (string, error)
+	// Post an ephemeral message to the user who made the request
+	RespondEphemeral(options ...slack.MsgOption) error
+	// Send a message to the request's response URL
+	Respond(message ResponseMessage) error
+	// The locale of the user who made the request
+	Locale() string
+	// Translate a message into the user's locale
+	T(key string, args ...any) string
+}
+
+var (
+	_ Request = (*CommandRequest)(nil)
+	_ Request = (*BlockActionRequest)(nil)
+	_ Request = (*ViewSubmissionRequest)(nil)
+	_ Request = (*ViewClosedRequest)(nil)
+	_ Request = (*EventRequest)(nil)
+	_ Request = (*MessageShortcutRequest)(nil)
+)
+
+// Implemented by the requests created by an Application
+type applicationRequest interface {
+	base() *baseRequest
+}
+
+func (req *baseRequest) base() *baseRequest {
+	return req
+}
+
+// The base of a request created by an Application, or nil for
+// another implementation of Request.
+func baseOf(req Request) *baseRequest {
+	if r, ok := req.(applicationRequest); ok {
+		return r.base()
+	}
+	return nil
+}
+
+// Returns the IDs and URLs of the request.
+func (req *baseRequest) RequestContext() RequestContext {
+	return req.context
+}
@@ -0,0 +1,162 @@
+package slap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+func captureContext(contexts chan slap.RequestContext) func(req slap.Request) error {
+	return func(req slap.Request) error {
+		contexts <- req.RequestContext()
+		req.Ack()
+		return nil
+	}
+}
+
+func TestRequestContext(t *testing.T) {
+	t.Parallel()
+
+	contexts := make(chan slap.RequestContext, 1)
+	handle := captureContext(contexts)
+
+	app, router := createTestApp()
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error { return handle(req) })
+	app.RegisterBlockAction("action", func(req *slap.BlockActionRequest) error { return handle(req) })
+	app.RegisterViewSubmission("form", func(req *slap.ViewSubmissionRequest) error { return handle(req) })
+	app.RegisterEventHandler("message", func(req *slap.EventRequest) error { return handle(req) })
+	app.RegisterMessageShortcut("shortcut", func(req *slap.MessageShortcutRequest) error { return handle(req) })
+	tester := slaptest.New(router, "signing-secret")
+
+	message := slack.Message{}
+	message.Timestamp = "1234.0002"
+	message.ThreadTimestamp = "1234.0001"
+
+	tests := map[string]struct {
+		send func()
+		want slap.RequestContext
+	}{
+		"command": {
+			send: func() {
+				tester.Command(slaptest.Command{Command: "/help", ResponseURL: "https://hooks.slack.com/commands/1"})
+			},
+			want: slap.RequestContext{
+				TeamID:      slaptest.DefaultTeamID,
+				UserID:      slaptest.DefaultUserID,
+				ChannelID:   slaptest.DefaultChannelID,
+				APIAppID:    slaptest.DefaultAppID,
+				TriggerID:   slaptest.DefaultTriggerID,
+				ResponseURL: "https://hooks.slack.com/commands/1",
+			},
+		},
+		"block action": {
+			send: func() {
+				tester.BlockAction(slaptest.BlockAction{
+					Action:      slack.BlockAction{ActionID: "action"},
+					Message:     &message,
+					ResponseURL: "https://hooks.slack.com/actions/1",
+				})
+			},
+			want: slap.RequestContext{
+				TeamID:          slaptest.DefaultTeamID,
+				UserID:          slaptest.DefaultUserID,
+				ChannelID:       slaptest.DefaultChannelID,
+				ThreadTimestamp: "1234.0001",
+				APIAppID:        slaptest.DefaultAppID,
+				TriggerID:       slaptest.DefaultTriggerID,
+				ResponseURL:     "https://hooks.slack.com/actions/1",
+			},
+		},
+		"view submission": {
+			send: func() {
+				tester.ViewSubmission(slaptest.ViewSubmission{View: slack.View{CallbackID: "form"}})
+			},
+			want: slap.RequestContext{
+				TeamID:    slaptest.DefaultTeamID,
+				UserID:    slaptest.DefaultUserID,
+				APIAppID:  slaptest.DefaultAppID,
+				TriggerID: slaptest.DefaultTriggerID,
+			},
+		},
+		"event": {
+			send: func() {
+				tester.Event(slaptest.Event{
+					EnterpriseID: "E0123456",
+					Event: map[string]any{
+						"type":    "message",
+						"user":    "U0654321",
+						"channel": "C0654321",
+						"ts":      "1234.0001",
+					},
+				})
+			},
+			want: slap.RequestContext{
+				EnterpriseID: "E0123456",
+				TeamID:       slaptest.DefaultTeamID,
+				UserID:       "U0654321",
+				ChannelID:    "C0654321",
+				APIAppID:     slaptest.DefaultAppID,
+			},
+		},
+		"message shortcut": {
+			send: func() {
+				tester.MessageShortcut(slaptest.MessageShortcut{CallbackID: "shortcut", Message: message.Msg})
+			},
+			want: slap.RequestContext{
+				TeamID:          slaptest.DefaultTeamID,
+				UserID:          slaptest.DefaultUserID,
+				ChannelID:       slaptest.DefaultChannelID,
+				ThreadTimestamp: "1234.0001",
+				APIAppID:        slaptest.DefaultAppID,
+				TriggerID:       slaptest.DefaultTriggerID,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test.send()
+		got := <-contexts
+		if got != test.want {
+			t.Errorf("Unexpected %v context, got: %+v, want: %+v", name, got, test.want)
+		}
+	}
+}
+
+// A Request for testing helpers without an Application
+type fakeRequest struct {
+	context   slap.RequestContext
+	ephemeral []string
+}
+
+func (r *fakeRequest) RequestContext() slap.RequestContext { return r.context }
+func (r *fakeRequest) Ack()                                {}
+func (r *fakeRequest) Client() *slack.Client               { return slack.New("xoxb-test") }
+func (r *fakeRequest) UserClient() (*slack.Client, error)  { return nil, slap.ErrNoUserTokenSource }
+func (r *fakeRequest) Say(options ...slack.MsgOption) (string, error) {
+	return "", slap.ErrNoConversation
+}
+func (r *fakeRequest) RespondEphemeral(options ...slack.MsgOption) error {
+	_, values, err := slack.UnsafeApplyMsgOptions("", "", "", options...)
+	if err != nil {
+		return err
+	}
+	r.ephemeral = append(r.ephemeral, values.Get("text"))
+	return nil
+}
+func (r *fakeRequest) Respond(message slap.ResponseMessage) error { return nil }
+func (r *fakeRequest) Locale() string                             { return "en" }
+func (r *fakeRequest) T(key string, args ...any) string           { return "translated " + key }
+
+func TestFakeRequest(t *testing.T) {
+	t.Parallel()
+
+	req := &fakeRequest{context: slap.RequestContext{UserID: "U0123456"}}
+	slap.DefaultErrorHandler("errors.generic")(req, slap.CommandRequestKind, nil, errors.New("failed"))
+
+	if len(req.ephemeral) != 1 || req.ephemeral[0] != "translated errors.generic" {
+		t.Errorf("Unexpected ephemeral messages: %v", req.ephemeral)
+	}
+}
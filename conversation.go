@@ -0,0 +1,62 @@
+package slap
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/slack-go/slack"
+)
+
+// The request did not come from a conversation, e.g. a view submission
+var ErrNoConversation = errors.New("Request has no conversation to reply to")
+
+// The conversation of an inner event, which some event types
+// send in an item or as an object.
+type innerEventConversation struct {
+	Channel         json.RawMessage `json:"channel"`
+	ThreadTimestamp string          `json:"thread_ts"`
+	Item            struct {
+		Channel string `json:"channel"`
+	} `json:"item"`
+}
+
+func (c innerEventConversation) channelID() string {
+	var id string
+	if err := json.Unmarshal(c.Channel, &id); err != nil || id == "" {
+		return c.Item.Channel
+	}
+	return id
+}
+
+// Posts a message to the conversation the request came from,
+// replying in the thread if the request came from a threaded message.
+// Returns the timestamp of the message.
+//
+// Returns ErrNoConversation if the request has no conversation,
+// e.g. a view submission or an action in the Home tab.
+func (req *baseRequest) Say(options ...slack.MsgOption) (string, error) {
+	if req.context.ChannelID == "" {
+		return "", ErrNoConversation
+	}
+	_, timestamp, err := req.Client().PostMessage(req.context.ChannelID, req.threaded(options)...)
+	return timestamp, err
+}
+
+// Posts an ephemeral message, only visible to the user who made the
+// request, to the conversation the request came from.
+//
+// Returns ErrNoConversation if the request has no conversation or user.
+func (req *baseRequest) RespondEphemeral(options ...slack.MsgOption) error {
+	if req.context.ChannelID == "" || req.context.UserID == "" {
+		return ErrNoConversation
+	}
+	_, err := req.Client().PostEphemeral(req.context.ChannelID, req.context.UserID, req.threaded(options)...)
+	return err
+}
+
+func (req *baseRequest) threaded(options []slack.MsgOption) []slack.MsgOption {
+	if req.context.ThreadTimestamp == "" {
+		return options
+	}
+	return append([]slack.MsgOption{slack.MsgOptionTS(req.context.ThreadTimestamp)}, options...)
+}
@@ -0,0 +1,153 @@
+package slap_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+func createConversationTestApp() (*slap.Application, *slaptest.Tester, *slaptest.Server) {
+	server := slaptest.NewServer()
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		APIURL: server.APIURL(),
+	})
+	return app, slaptest.New(router, "signing-secret"), server
+}
+
+func TestCommandSay(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.Ack()
+		_, err := req.Say(slack.MsgOptionText("Hello!", false))
+		if err != nil {
+			return err
+		}
+		return req.RespondEphemeral(slack.MsgOptionText("Psst", false))
+	})
+
+	tester.Command(slaptest.Command{Command: "/help", ChannelID: "C0654321", UserID: "U0654321"})
+
+	call, err := server.WaitForCall("chat.postMessage", time.Second)
+	if err != nil {
+		t.Fatalf("Expected a message: %v", err.Error())
+	}
+	channelGot, channelWant := call.Params.Get("channel"), "C0654321"
+	if channelGot != channelWant {
+		t.Errorf("Unexpected channel, got: %v, want: %v", channelGot, channelWant)
+	}
+
+	call, err = server.WaitForCall("chat.postEphemeral", time.Second)
+	if err != nil {
+		t.Fatalf("Expected an ephemeral message: %v", err.Error())
+	}
+	userGot, userWant := call.Params.Get("user"), "U0654321"
+	if userGot != userWant {
+		t.Errorf("Unexpected user, got: %v, want: %v", userGot, userWant)
+	}
+}
+
+func TestEventSayInThread(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+	app.RegisterEventHandler("message", func(req *slap.EventRequest) error {
+		req.Ack()
+		_, err := req.Say(slack.MsgOptionText("Hello!", false))
+		return err
+	})
+
+	tester.Event(slaptest.Event{Event: map[string]any{
+		"type":      "message",
+		"channel":   "C0654321",
+		"user":      "U0654321",
+		"text":      "Hi",
+		"ts":        "1234.0002",
+		"thread_ts": "1234.0001",
+	}})
+
+	call, err := server.WaitForCall("chat.postMessage", time.Second)
+	if err != nil {
+		t.Fatalf("Expected a message: %v", err.Error())
+	}
+
+	channelGot, channelWant := call.Params.Get("channel"), "C0654321"
+	if channelGot != channelWant {
+		t.Errorf("Unexpected channel, got: %v, want: %v", channelGot, channelWant)
+	}
+
+	threadGot, threadWant := call.Params.Get("thread_ts"), "1234.0001"
+	if threadGot != threadWant {
+		t.Errorf("Unexpected thread, got: %v, want: %v", threadGot, threadWant)
+	}
+}
+
+func TestBlockActionSayInMessageThread(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+	app.RegisterBlockAction("action", func(req *slap.BlockActionRequest) error {
+		req.Ack()
+		return req.RespondEphemeral(slack.MsgOptionText("Clicked", false))
+	})
+
+	message := slack.Message{}
+	message.Timestamp = "1234.0002"
+	message.ThreadTimestamp = "1234.0001"
+	tester.BlockAction(slaptest.BlockAction{
+		Action:    slack.BlockAction{ActionID: "action"},
+		ChannelID: "C0654321",
+		Message:   &message,
+	})
+
+	call, err := server.WaitForCall("chat.postEphemeral", time.Second)
+	if err != nil {
+		t.Fatalf("Expected an ephemeral message: %v", err.Error())
+	}
+
+	threadGot, threadWant := call.Params.Get("thread_ts"), "1234.0001"
+	if threadGot != threadWant {
+		t.Errorf("Unexpected thread, got: %v, want: %v", threadGot, threadWant)
+	}
+}
+
+func TestViewSubmissionSayNoConversation(t *testing.T) {
+	t.Parallel()
+
+	errs := make(chan error, 2)
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+	app.RegisterViewSubmission("form", func(req *slap.ViewSubmissionRequest) error {
+		_, err := req.Say(slack.MsgOptionText("Hello!", false))
+		errs <- err
+		errs <- req.RespondEphemeral(slack.MsgOptionText("Hello!", false))
+		req.Ack()
+		return nil
+	})
+
+	tester.ViewSubmission(slaptest.ViewSubmission{View: slack.View{CallbackID: "form"}})
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, slap.ErrNoConversation) {
+			t.Errorf("Unexpected error, got: %v, want: %v", err, slap.ErrNoConversation)
+		}
+	}
+	if calls := server.Calls("chat.postMessage"); len(calls) != 0 {
+		t.Errorf("Unexpected messages: %v", len(calls))
+	}
+}
@@ -0,0 +1,321 @@
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+// A slash command response action, with blocks that can be decoded.
+type commandResponse struct {
+	ResponseType string       `json:"response_type"`
+	Text         string       `json:"text"`
+	Blocks       slack.Blocks `json:"blocks"`
+}
+
+// A view submission response action, with a view that can be decoded.
+type viewResponse struct {
+	ResponseAction string            `json:"response_action"`
+	View           *slack.View       `json:"view"`
+	Errors         map[string]string `json:"errors"`
+}
+
+// Runs a slash command as a user in a channel, e.g.
+// RunCommand("U0123456", "C0123456", "/start", "now").
+//
+// Messages in the command's response are posted to the workspace.
+func (ws *Workspace) RunCommand(userID string, channelID string, command string, text string) (*slaptest.Response, error) {
+	ws.mu.Lock()
+	if _, ok := ws.users[userID]; !ok {
+		ws.mu.Unlock()
+		return nil, fmt.Errorf("Unknown user %v", userID)
+	}
+	channel, ok := ws.channel(channelID)
+	if !ok {
+		ws.mu.Unlock()
+		return nil, fmt.Errorf("Unknown channel %v", channelID)
+	}
+	trigger := ws.newTrigger(userID)
+	ws.mu.Unlock()
+
+	res := ws.tester.Command(slaptest.Command{
+		Command:     command,
+		Text:        text,
+		TeamID:      TeamID,
+		ChannelID:   channel.ID,
+		ChannelName: channel.Name,
+		UserID:      userID,
+		TriggerID:   trigger,
+		APIAppID:    AppID,
+	})
+	if res.StatusCode != http.StatusOK {
+		return res, fmt.Errorf("Command %v failed with status %v", command, res.StatusCode)
+	}
+	if len(res.Body) == 0 {
+		return res, nil
+	}
+
+	var action commandResponse
+	if err := res.JSON(&action); err != nil {
+		return res, err
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	msg := Message{
+		Channel:   channel.ID,
+		Timestamp: ws.nextTimestamp(),
+		User:      BotUserID,
+		BotID:     BotID,
+		Text:      action.Text,
+		Blocks:    action.Blocks,
+	}
+	if action.ResponseType != "in_channel" {
+		msg.EphemeralTo = userID
+	}
+	ws.messages = append(ws.messages, msg)
+	return res, nil
+}
+
+// Clicks a button in a message as a user.
+func (ws *Workspace) ClickButton(userID string, channelID string, ts string, actionID string) (*slaptest.Response, error) {
+	ws.mu.Lock()
+	found := ws.findMessage(channelID, ts)
+	if found == nil || (found.EphemeralTo != "" && found.EphemeralTo != userID) {
+		ws.mu.Unlock()
+		return nil, fmt.Errorf("Message %v not found in %v", ts, channelID)
+	}
+	msg := *found
+	trigger := ws.newTrigger(userID)
+	ws.mu.Unlock()
+
+	action, ok := findButton(msg.Blocks.BlockSet, actionID)
+	if !ok {
+		return nil, fmt.Errorf("Button %v not found in message %v", actionID, ts)
+	}
+
+	res := ws.tester.BlockAction(slaptest.BlockAction{
+		TeamID:    TeamID,
+		UserID:    userID,
+		ChannelID: channelID,
+		TriggerID: trigger,
+		APIAppID:  AppID,
+		Action:    action,
+		Message: &slack.Message{Msg: slack.Msg{
+			Type:      "message",
+			User:      msg.User,
+			BotID:     msg.BotID,
+			Text:      msg.Text,
+			Timestamp: msg.Timestamp,
+			Blocks:    msg.Blocks,
+		}},
+	})
+	if res.StatusCode != http.StatusOK {
+		return res, fmt.Errorf("Block action %v failed with status %v", actionID, res.StatusCode)
+	}
+	return res, nil
+}
+
+// Clicks a button in a user's top-most modal.
+func (ws *Workspace) ClickModalButton(userID string, actionID string) (*slaptest.Response, error) {
+	ws.mu.Lock()
+	stack := ws.modals[userID]
+	if len(stack) == 0 {
+		ws.mu.Unlock()
+		return nil, fmt.Errorf("User %v has no open modal", userID)
+	}
+	view := stack[len(stack)-1].View
+	trigger := ws.newTrigger(userID)
+	ws.mu.Unlock()
+
+	action, ok := findButton(view.Blocks.BlockSet, actionID)
+	if !ok {
+		return nil, fmt.Errorf("Button %v not found in view %v", actionID, view.ID)
+	}
+
+	res := ws.tester.BlockAction(slaptest.BlockAction{
+		TeamID:    TeamID,
+		UserID:    userID,
+		TriggerID: trigger,
+		APIAppID:  AppID,
+		Action:    action,
+		View:      &view,
+	})
+	if res.StatusCode != http.StatusOK {
+		return res, fmt.Errorf("Block action %v failed with status %v", actionID, res.StatusCode)
+	}
+	return res, nil
+}
+
+// Submits a user's top-most modal with input values by
+// block ID and action ID, then applies the app's response action.
+// Returns an error if the app pushes a view onto a full modal stack.
+func (ws *Workspace) SubmitModal(userID string, values map[string]map[string]slack.BlockAction) (*slaptest.Response, error) {
+	ws.mu.Lock()
+	stack := ws.modals[userID]
+	if len(stack) == 0 {
+		ws.mu.Unlock()
+		return nil, fmt.Errorf("User %v has no open modal", userID)
+	}
+	view := stack[len(stack)-1].View
+	trigger := ws.newTrigger(userID)
+	ws.mu.Unlock()
+
+	res := ws.tester.ViewSubmission(slaptest.ViewSubmission{
+		TeamID:    TeamID,
+		UserID:    userID,
+		TriggerID: trigger,
+		APIAppID:  AppID,
+		View:      view,
+		Values:    values,
+	})
+	if res.StatusCode != http.StatusOK {
+		return res, fmt.Errorf("View submission %v failed with status %v", view.CallbackID, res.StatusCode)
+	}
+
+	var action viewResponse
+	if len(res.Body) > 0 {
+		if err := res.JSON(&action); err != nil {
+			return res, err
+		}
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	stack = ws.modals[userID]
+	if len(stack) == 0 {
+		return res, nil
+	}
+	top := len(stack) - 1
+
+	switch action.ResponseAction {
+	case "clear":
+		delete(ws.modals, userID)
+	case "errors":
+		stack[top].Errors = action.Errors
+	case "update":
+		if action.View != nil {
+			updated := *action.View
+			updated.ID = stack[top].View.ID
+			stack[top] = Modal{User: userID, View: updated}
+		}
+	case "push":
+		if len(stack) >= maxModalViews {
+			return res, fmt.Errorf("View submission %v pushed more than %v views", view.CallbackID, maxModalViews)
+		}
+		if action.View != nil {
+			pushed := *action.View
+			pushed.ID = ws.nextID("V")
+			pushed.RootViewID = stack[0].View.ID
+			pushed.PreviousViewID = stack[top].View.ID
+			ws.modals[userID] = append(stack, Modal{User: userID, View: pushed})
+		}
+	default:
+		// Submitting without a response action closes the modal
+		ws.modals[userID] = stack[:top]
+	}
+	return res, nil
+}
+
+// Posts a message as a user and sends the app a "message" event,
+// and an "app_mention" event if the app's bot user is mentioned.
+func (ws *Workspace) SendMessage(userID string, channelID string, text string) (Message, error) {
+	ws.mu.Lock()
+	if _, ok := ws.users[userID]; !ok {
+		ws.mu.Unlock()
+		return Message{}, fmt.Errorf("Unknown user %v", userID)
+	}
+	channel, ok := ws.channel(channelID)
+	if !ok {
+		ws.mu.Unlock()
+		return Message{}, fmt.Errorf("Unknown channel %v", channelID)
+	}
+	msg := Message{
+		Channel:   channel.ID,
+		Timestamp: ws.nextTimestamp(),
+		User:      userID,
+		Text:      text,
+	}
+	ws.messages = append(ws.messages, msg)
+	eventID := ws.nextID("Ev")
+	ws.mu.Unlock()
+
+	channelType := "channel"
+	if channel.IsIM {
+		channelType = "im"
+	} else if channel.IsPrivate {
+		channelType = "group"
+	}
+
+	event := map[string]string{
+		"type":         "message",
+		"channel":      msg.Channel,
+		"channel_type": channelType,
+		"user":         userID,
+		"text":         text,
+		"ts":           msg.Timestamp,
+		"event_ts":     msg.Timestamp,
+	}
+	if res := ws.tester.Event(slaptest.Event{TeamID: TeamID, APIAppID: AppID, EventID: eventID, Event: event}); res.StatusCode != http.StatusOK {
+		return msg, fmt.Errorf("Message event failed with status %v", res.StatusCode)
+	}
+
+	if strings.Contains(text, "<@"+BotUserID+">") {
+		mention := map[string]string{}
+		for key, value := range event {
+			mention[key] = value
+		}
+		mention["type"] = "app_mention"
+		delete(mention, "channel_type")
+		if res := ws.tester.Event(slaptest.Event{TeamID: TeamID, APIAppID: AppID, EventID: eventID + "M", Event: mention}); res.StatusCode != http.StatusOK {
+			return msg, fmt.Errorf("App mention event failed with status %v", res.StatusCode)
+		}
+	}
+	return msg, nil
+}
+
+// Finds a button by action ID in sections and actions blocks.
+func findButton(blocks []slack.Block, actionID string) (slack.BlockAction, bool) {
+	for _, block := range blocks {
+		var buttons []*slack.ButtonBlockElement
+		var blockID string
+		switch b := block.(type) {
+		case *slack.SectionBlock:
+			blockID = b.BlockID
+			if b.Accessory != nil && b.Accessory.ButtonElement != nil {
+				buttons = append(buttons, b.Accessory.ButtonElement)
+			}
+		case *slack.ActionBlock:
+			blockID = b.BlockID
+			if b.Elements != nil {
+				for _, element := range b.Elements.ElementSet {
+					if button, ok := element.(*slack.ButtonBlockElement); ok {
+						buttons = append(buttons, button)
+					}
+				}
+			}
+		}
+		for _, button := range buttons {
+			if button.ActionID == actionID {
+				return slack.BlockAction{
+					ActionID: button.ActionID,
+					BlockID:  blockID,
+					Type:     "button",
+					Value:    button.Value,
+					Text:     ptrValue(button.Text),
+				}, true
+			}
+		}
+	}
+	return slack.BlockAction{}, false
+}
+
+func ptrValue(text *slack.TextBlockObject) slack.TextBlockObject {
+	if text == nil {
+		return slack.TextBlockObject{}
+	}
+	return *text
+}
@@ -0,0 +1,390 @@
+package emulator
+
+import (
+	"encoding/json"
+
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+type response = map[string]any
+
+func errorResponse(err string) response {
+	return response{"ok": false, "error": err}
+}
+
+func (ws *Workspace) registerWebAPI() {
+	ws.server.Handle("auth.test", ws.authTest)
+	ws.server.Handle("chat.postMessage", ws.chatPostMessage)
+	ws.server.Handle("chat.postEphemeral", ws.chatPostEphemeral)
+	ws.server.Handle("chat.update", ws.chatUpdate)
+	ws.server.Handle("chat.delete", ws.chatDelete)
+	ws.server.Handle("views.open", ws.viewsOpen)
+	ws.server.Handle("views.push", ws.viewsPush)
+	ws.server.Handle("views.update", ws.viewsUpdate)
+	ws.server.Handle("views.publish", ws.viewsPublish)
+	ws.server.Handle("conversations.info", ws.conversationsInfo)
+	ws.server.Handle("conversations.list", ws.conversationsList)
+	ws.server.Handle("conversations.history", ws.conversationsHistory)
+	ws.server.Handle("conversations.replies", ws.conversationsReplies)
+	ws.server.Handle("conversations.members", ws.conversationsMembers)
+	ws.server.Handle("conversations.open", ws.conversationsOpen)
+	ws.server.Handle("users.info", ws.usersInfo)
+}
+
+func (ws *Workspace) authTest(call slaptest.Call) any {
+	return response{"ok": true, "team_id": TeamID, "user_id": BotUserID, "bot_id": BotID}
+}
+
+func (ws *Workspace) chatPostMessage(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	channel, ok := ws.channel(call.Params.Get("channel"))
+	if !ok {
+		return errorResponse("channel_not_found")
+	}
+	blocks, err := parseBlocks(call.Params.Get("blocks"))
+	if err != nil {
+		return errorResponse("invalid_blocks")
+	}
+
+	msg := Message{
+		Channel:         channel.ID,
+		Timestamp:       ws.nextTimestamp(),
+		ThreadTimestamp: call.Params.Get("thread_ts"),
+		User:            BotUserID,
+		BotID:           BotID,
+		Text:            call.Params.Get("text"),
+		Blocks:          blocks,
+	}
+	ws.messages = append(ws.messages, msg)
+	return response{"ok": true, "channel": msg.Channel, "ts": msg.Timestamp, "message": messageJSON(msg)}
+}
+
+func (ws *Workspace) chatPostEphemeral(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	channel, ok := ws.channel(call.Params.Get("channel"))
+	if !ok {
+		return errorResponse("channel_not_found")
+	}
+	userID := call.Params.Get("user")
+	if _, ok := ws.users[userID]; !ok {
+		return errorResponse("user_not_found")
+	}
+	blocks, err := parseBlocks(call.Params.Get("blocks"))
+	if err != nil {
+		return errorResponse("invalid_blocks")
+	}
+
+	msg := Message{
+		Channel:         channel.ID,
+		Timestamp:       ws.nextTimestamp(),
+		ThreadTimestamp: call.Params.Get("thread_ts"),
+		User:            BotUserID,
+		BotID:           BotID,
+		Text:            call.Params.Get("text"),
+		Blocks:          blocks,
+		EphemeralTo:     userID,
+	}
+	ws.messages = append(ws.messages, msg)
+	return response{"ok": true, "message_ts": msg.Timestamp}
+}
+
+func (ws *Workspace) chatUpdate(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	msg := ws.findMessage(call.Params.Get("channel"), call.Params.Get("ts"))
+	if msg == nil {
+		return errorResponse("message_not_found")
+	}
+	blocks, err := parseBlocks(call.Params.Get("blocks"))
+	if err != nil {
+		return errorResponse("invalid_blocks")
+	}
+	msg.Text = call.Params.Get("text")
+	msg.Blocks = blocks
+	return response{"ok": true, "channel": msg.Channel, "ts": msg.Timestamp, "text": msg.Text}
+}
+
+func (ws *Workspace) chatDelete(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	channelID, ts := call.Params.Get("channel"), call.Params.Get("ts")
+	if !ws.deleteMessage(channelID, ts) {
+		return errorResponse("message_not_found")
+	}
+	return response{"ok": true, "channel": channelID, "ts": ts}
+}
+
+func (ws *Workspace) viewsOpen(call slaptest.Call) any {
+	return ws.openModal(call, false)
+}
+
+func (ws *Workspace) viewsPush(call slaptest.Call) any {
+	return ws.openModal(call, true)
+}
+
+// Slack allows up to 3 views in a modal stack
+const maxModalViews = 3
+
+func (ws *Workspace) openModal(call slaptest.Call, push bool) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	triggerID := call.Params.Get("trigger_id")
+	userID, ok := ws.triggers[triggerID]
+	if !ok {
+		return errorResponse("invalid_trigger_id")
+	}
+
+	stack := ws.modals[userID]
+	if push && len(stack) == 0 {
+		return errorResponse("not_found")
+	}
+	if !push {
+		stack = nil
+	}
+	if len(stack) >= maxModalViews {
+		return errorResponse("push_limit_reached")
+	}
+
+	view, err := ws.parseView(call.Params.Get("view"))
+	if err != nil {
+		return errorResponse("invalid_arguments")
+	}
+	// Trigger IDs can only be used once
+	delete(ws.triggers, triggerID)
+
+	if len(stack) > 0 {
+		view.RootViewID = stack[0].View.ID
+		view.PreviousViewID = stack[len(stack)-1].View.ID
+	} else {
+		view.RootViewID = view.ID
+	}
+	ws.modals[userID] = append(stack, Modal{User: userID, View: view})
+	return response{"ok": true, "view": view}
+}
+
+func (ws *Workspace) viewsUpdate(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	view, err := ws.parseView(call.Params.Get("view"))
+	if err != nil {
+		return errorResponse("invalid_arguments")
+	}
+
+	viewID, externalID := call.Params.Get("view_id"), call.Params.Get("external_id")
+	for userID, stack := range ws.modals {
+		for i, modal := range stack {
+			if (viewID != "" && modal.View.ID == viewID) || (externalID != "" && modal.View.ExternalID == externalID) {
+				view.ID = modal.View.ID
+				view.RootViewID = modal.View.RootViewID
+				view.PreviousViewID = modal.View.PreviousViewID
+				ws.modals[userID][i] = Modal{User: userID, View: view}
+				return response{"ok": true, "view": view}
+			}
+		}
+	}
+	return errorResponse("not_found")
+}
+
+func (ws *Workspace) viewsPublish(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	userID := call.Params.Get("user_id")
+	if _, ok := ws.users[userID]; !ok {
+		return errorResponse("user_not_found")
+	}
+	view, err := ws.parseView(call.Params.Get("view"))
+	if err != nil {
+		return errorResponse("invalid_arguments")
+	}
+	ws.homeViews[userID] = view
+	return response{"ok": true, "view": view}
+}
+
+func (ws *Workspace) conversationsInfo(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	channel, ok := ws.channels[call.Params.Get("channel")]
+	if !ok {
+		return errorResponse("channel_not_found")
+	}
+	return response{"ok": true, "channel": channelJSON(channel)}
+}
+
+func (ws *Workspace) conversationsList(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	channels := []response{}
+	for _, channel := range ws.channels {
+		channels = append(channels, channelJSON(channel))
+	}
+	return response{"ok": true, "channels": channels, "response_metadata": response{"next_cursor": ""}}
+}
+
+func (ws *Workspace) conversationsHistory(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	channelID := call.Params.Get("channel")
+	if _, ok := ws.channels[channelID]; !ok {
+		return errorResponse("channel_not_found")
+	}
+
+	// Newest messages first, excluding thread replies
+	messages := []response{}
+	for i := len(ws.messages) - 1; i >= 0; i-- {
+		msg := ws.messages[i]
+		if msg.Channel != channelID || msg.EphemeralTo != "" {
+			continue
+		}
+		if msg.ThreadTimestamp != "" && msg.ThreadTimestamp != msg.Timestamp {
+			continue
+		}
+		messages = append(messages, messageJSON(msg))
+	}
+	return response{"ok": true, "messages": messages, "has_more": false}
+}
+
+func (ws *Workspace) conversationsReplies(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	channelID, ts := call.Params.Get("channel"), call.Params.Get("ts")
+	messages := []response{}
+	for _, msg := range ws.messages {
+		if msg.Channel != channelID || msg.EphemeralTo != "" {
+			continue
+		}
+		if msg.Timestamp == ts || msg.ThreadTimestamp == ts {
+			messages = append(messages, messageJSON(msg))
+		}
+	}
+	if len(messages) == 0 {
+		return errorResponse("thread_not_found")
+	}
+	return response{"ok": true, "messages": messages, "has_more": false}
+}
+
+func (ws *Workspace) conversationsMembers(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	channel, ok := ws.channels[call.Params.Get("channel")]
+	if !ok {
+		return errorResponse("channel_not_found")
+	}
+	return response{"ok": true, "members": channel.Members, "response_metadata": response{"next_cursor": ""}}
+}
+
+func (ws *Workspace) conversationsOpen(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	userID := call.Params.Get("users")
+	if _, ok := ws.users[userID]; !ok {
+		return errorResponse("user_not_found")
+	}
+	return response{"ok": true, "channel": channelJSON(ws.openIM(userID))}
+}
+
+func (ws *Workspace) usersInfo(call slaptest.Call) any {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	user, ok := ws.users[call.Params.Get("user")]
+	if !ok {
+		return errorResponse("user_not_found")
+	}
+	return response{"ok": true, "user": response{
+		"id":        user.ID,
+		"team_id":   TeamID,
+		"name":      user.Name,
+		"real_name": user.RealName,
+		"is_bot":    user.IsBot,
+		"profile":   response{"real_name": user.RealName, "display_name": user.Name},
+	}}
+}
+
+// Finds a message, including ephemeral messages. Must be called with the lock held.
+func (ws *Workspace) findMessage(channelID string, ts string) *Message {
+	for i := range ws.messages {
+		if ws.messages[i].Channel == channelID && ws.messages[i].Timestamp == ts {
+			return &ws.messages[i]
+		}
+	}
+	return nil
+}
+
+// Deletes a message. Must be called with the lock held.
+func (ws *Workspace) deleteMessage(channelID string, ts string) bool {
+	for i, msg := range ws.messages {
+		if msg.Channel == channelID && msg.Timestamp == ts {
+			ws.messages = append(ws.messages[:i], ws.messages[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Parses a view and assigns it an ID. Must be called with the lock held.
+func (ws *Workspace) parseView(blob string) (slack.View, error) {
+	var view slack.View
+	if err := json.Unmarshal([]byte(blob), &view); err != nil {
+		return view, err
+	}
+	view.ID = ws.nextID("V")
+	view.TeamID = TeamID
+	view.AppID = AppID
+	view.BotID = BotID
+	return view, nil
+}
+
+func parseBlocks(blob string) (slack.Blocks, error) {
+	var blocks slack.Blocks
+	if blob == "" {
+		return blocks, nil
+	}
+	err := json.Unmarshal([]byte(blob), &blocks)
+	return blocks, err
+}
+
+func messageJSON(msg Message) response {
+	res := response{
+		"type":   "message",
+		"user":   msg.User,
+		"text":   msg.Text,
+		"ts":     msg.Timestamp,
+		"blocks": msg.Blocks.BlockSet,
+	}
+	if msg.BotID != "" {
+		res["bot_id"] = msg.BotID
+	}
+	if msg.ThreadTimestamp != "" {
+		res["thread_ts"] = msg.ThreadTimestamp
+	}
+	return res
+}
+
+func channelJSON(channel *Channel) response {
+	return response{
+		"id":          channel.ID,
+		"name":        channel.Name,
+		"is_channel":  !channel.IsIM && !channel.IsPrivate,
+		"is_group":    !channel.IsIM && channel.IsPrivate,
+		"is_im":       channel.IsIM,
+		"is_private":  channel.IsPrivate,
+		"is_member":   true,
+		"num_members": len(channel.Members),
+	}
+}
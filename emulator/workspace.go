@@ -0,0 +1,231 @@
+// This package contains a local Slack workspace emulator for
+// developing and testing Slap Applications end-to-end.
+//
+// A Workspace holds users, channels, messages and modals, serves
+// a subset of the Slack Web API for the app's Slack clients, and
+// drives the app by sending it signed slash commands,
+// interactions and events.
+package emulator
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+// The IDs of the emulated workspace and app.
+const (
+	TeamID    = slaptest.DefaultTeamID
+	AppID     = slaptest.DefaultAppID
+	BotUserID = "U0000BOT"
+	BotID     = "B0000BOT"
+)
+
+// A member of the workspace.
+type User struct {
+	ID       string
+	Name     string
+	RealName string
+	IsBot    bool
+}
+
+// A conversation in the workspace.
+type Channel struct {
+	ID        string
+	Name      string
+	IsPrivate bool
+	// Whether the channel is a direct message with a user.
+	IsIM bool
+	// The user IDs of the channel's members.
+	Members []string
+}
+
+// A message posted to a conversation.
+type Message struct {
+	Channel         string
+	Timestamp       string
+	ThreadTimestamp string
+	// The ID of the user who posted the message.
+	User string
+	// The bot ID if the app posted the message.
+	BotID  string
+	Text   string
+	Blocks slack.Blocks
+	// The ID of the only user who can see an ephemeral message.
+	EphemeralTo string
+}
+
+// A modal open for a user.
+type Modal struct {
+	User string
+	View slack.View
+	// The errors from the last "errors" response action by block ID.
+	Errors map[string]string
+}
+
+// An emulated Slack workspace.
+type Workspace struct {
+	mu        sync.Mutex
+	server    *slaptest.Server
+	tester    *slaptest.Tester
+	users     map[string]*User
+	channels  map[string]*Channel
+	messages  []Message
+	modals    map[string][]Modal
+	homeViews map[string]slack.View
+	triggers  map[string]string
+	seq       int
+}
+
+// Creates a Workspace that sends requests to the router of a Slap
+// Application, and starts its Web API server.
+//
+// Pass Workspace.APIURL as slap.Config.APIURL so the app's Slack
+// clients use the Workspace.
+func New(router http.Handler, signingSecret string) *Workspace {
+	ws := &Workspace{
+		server:    slaptest.NewServer(),
+		tester:    slaptest.New(router, signingSecret),
+		users:     make(map[string]*User),
+		channels:  make(map[string]*Channel),
+		modals:    make(map[string][]Modal),
+		homeViews: make(map[string]slack.View),
+		triggers:  make(map[string]string),
+	}
+	ws.users[BotUserID] = &User{ID: BotUserID, Name: "app", IsBot: true}
+	ws.registerWebAPI()
+	return ws
+}
+
+// The Tester used to send requests to the app.
+//
+// Set its PathPrefix or SingleEndpoint to match slap.Config.
+func (ws *Workspace) Tester() *slaptest.Tester {
+	return ws.tester
+}
+
+// The Web API base URL of the Workspace.
+func (ws *Workspace) APIURL() string {
+	return ws.server.APIURL()
+}
+
+// Stops the Workspace's Web API server.
+func (ws *Workspace) Close() {
+	ws.server.Close()
+}
+
+// Adds a user to the workspace.
+func (ws *Workspace) AddUser(user User) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.users[user.ID] = &user
+}
+
+// Adds a channel to the workspace. The app's bot user is
+// added as a member of every channel.
+func (ws *Workspace) AddChannel(channel Channel) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	channel.Members = append(channel.Members, BotUserID)
+	ws.channels[channel.ID] = &channel
+}
+
+// Returns the messages in a conversation, excluding ephemeral messages.
+func (ws *Workspace) Messages(channelID string) []Message {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	var messages []Message
+	for _, msg := range ws.messages {
+		if msg.Channel == channelID && msg.EphemeralTo == "" {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// Returns the ephemeral messages shown to a user.
+func (ws *Workspace) EphemeralMessages(userID string) []Message {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	var messages []Message
+	for _, msg := range ws.messages {
+		if msg.EphemeralTo == userID {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// Returns the direct messages between the app and a user.
+func (ws *Workspace) DirectMessages(userID string) []Message {
+	return ws.Messages(imChannelID(userID))
+}
+
+// Returns a user's stack of open modals, top-most last.
+func (ws *Workspace) Modals(userID string) []Modal {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return append([]Modal(nil), ws.modals[userID]...)
+}
+
+// Returns the view published to a user's App Home.
+func (ws *Workspace) HomeView(userID string) (slack.View, bool) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	view, ok := ws.homeViews[userID]
+	return view, ok
+}
+
+// Returns the Web API calls made by the app.
+func (ws *Workspace) Calls(method string) []slaptest.Call {
+	return ws.server.Calls(method)
+}
+
+// Creates a new ID with a prefix. Must be called with the lock held.
+func (ws *Workspace) nextID(prefix string) string {
+	ws.seq++
+	return fmt.Sprintf("%v%07d", prefix, ws.seq)
+}
+
+// Creates a new message timestamp. Must be called with the lock held.
+func (ws *Workspace) nextTimestamp() string {
+	ws.seq++
+	return fmt.Sprintf("1700000000.%06d", ws.seq)
+}
+
+// Creates a trigger ID for a user. Must be called with the lock held.
+func (ws *Workspace) newTrigger(userID string) string {
+	trigger := ws.nextID("TRIGGER")
+	ws.triggers[trigger] = userID
+	return trigger
+}
+
+// Finds a channel by ID, opening a direct message for user IDs.
+// Must be called with the lock held.
+func (ws *Workspace) channel(id string) (*Channel, bool) {
+	if channel, ok := ws.channels[id]; ok {
+		return channel, true
+	}
+	if _, ok := ws.users[id]; ok {
+		return ws.openIM(id), true
+	}
+	return nil, false
+}
+
+// Opens a direct message with a user. Must be called with the lock held.
+func (ws *Workspace) openIM(userID string) *Channel {
+	id := imChannelID(userID)
+	channel, ok := ws.channels[id]
+	if !ok {
+		channel = &Channel{ID: id, IsIM: true, IsPrivate: true, Members: []string{userID, BotUserID}}
+		ws.channels[id] = channel
+	}
+	return channel
+}
+
+func imChannelID(userID string) string {
+	return "D" + userID
+}
@@ -0,0 +1,253 @@
+package emulator_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/blocks"
+	"github.com/jacob-ian/slap/emulator"
+	"github.com/slack-go/slack"
+)
+
+func createTestWorkspace() (*slap.Application, *emulator.Workspace) {
+	router := http.NewServeMux()
+	ws := emulator.New(router, "signing-secret")
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "xoxb-test", nil
+		},
+		APIURL: ws.APIURL(),
+	})
+	ws.AddUser(emulator.User{ID: "U0123456", Name: "alice", RealName: "Alice"})
+	ws.AddChannel(emulator.Channel{ID: "C0123456", Name: "general", Members: []string{"U0123456"}})
+	return app, ws
+}
+
+// Waits for a condition that handlers satisfy after acknowledging Slack.
+func eventually(condition func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
+func TestWorkspaceJourney(t *testing.T) {
+	t.Parallel()
+
+	app, ws := createTestWorkspace()
+	defer ws.Close()
+
+	app.RegisterCommand("/start", func(req *slap.CommandRequest) error {
+		req.AckWithAction(slap.CommandResponseAction{
+			ResponseType: slap.RespondEphemeral,
+			Text:         "Get started",
+			Blocks: []slack.Block{
+				slack.NewSectionBlock(
+					slack.NewTextBlockObject("plain_text", "Get started", false, false),
+					nil,
+					slack.NewAccessory(slack.NewButtonBlockElement("start-button", "go", slack.NewTextBlockObject("plain_text", "Go", false, false))),
+				),
+			},
+		})
+		return nil
+	})
+
+	app.RegisterBlockAction("start-button", func(req *slap.BlockActionRequest) error {
+		_, err := req.Client().OpenView(req.Payload.TriggerID, slack.ModalViewRequest{
+			Type:       "modal",
+			CallbackID: "form-modal",
+			Title:      slack.NewTextBlockObject("plain_text", "Form", false, false),
+			Submit:     slack.NewTextBlockObject("plain_text", "Submit", false, false),
+			Blocks: slack.Blocks{BlockSet: []slack.Block{
+				slack.NewInputBlock(
+					"input-block",
+					slack.NewTextBlockObject("plain_text", "Full Name", false, false),
+					nil,
+					slack.NewPlainTextInputBlockElement(nil, "full-name"),
+				),
+			}},
+		})
+		if err != nil {
+			return err
+		}
+		req.Ack()
+		return nil
+	})
+
+	app.RegisterViewSubmission("form-modal", func(req *slap.ViewSubmissionRequest) error {
+		name := req.Payload.View.State.Values["input-block"]["full-name"].Value
+		if name == "" {
+			req.AckWithAction(slap.ViewResponseAction{
+				ResponseAction: slap.ViewResponseErrors,
+				Errors:         map[string]string{"input-block": "Please enter your name"},
+			})
+			return nil
+		}
+		req.AckWithAction(slap.ViewResponseAction{ResponseAction: slap.ViewResponseClear})
+		_, _, err := req.Client().PostMessage(req.Payload.User.ID, slack.MsgOptionText("Hello "+name, false))
+		return err
+	})
+
+	if _, err := ws.RunCommand("U0123456", "C0123456", "/start", ""); err != nil {
+		t.Fatalf("Could not run command: %v", err.Error())
+	}
+
+	ephemeral := ws.EphemeralMessages("U0123456")
+	if len(ephemeral) != 1 {
+		t.Fatalf("Unexpected number of ephemeral messages, got: %v, want: %v", len(ephemeral), 1)
+	}
+
+	if _, err := ws.ClickButton("U0123456", "C0123456", ephemeral[0].Timestamp, "start-button"); err != nil {
+		t.Fatalf("Could not click button: %v", err.Error())
+	}
+
+	modals := ws.Modals("U0123456")
+	if len(modals) != 1 {
+		t.Fatalf("Unexpected number of modals, got: %v, want: %v", len(modals), 1)
+	}
+
+	_, err := ws.SubmitModal("U0123456", map[string]map[string]slack.BlockAction{
+		"input-block": {"full-name": {Value: ""}},
+	})
+	if err != nil {
+		t.Fatalf("Could not submit modal: %v", err.Error())
+	}
+
+	errorGot, errorWant := ws.Modals("U0123456")[0].Errors["input-block"], "Please enter your name"
+	if errorGot != errorWant {
+		t.Errorf("Unexpected modal error, got: %v, want: %v", errorGot, errorWant)
+	}
+
+	_, err = ws.SubmitModal("U0123456", map[string]map[string]slack.BlockAction{
+		"input-block": {"full-name": {Value: "Alice"}},
+	})
+	if err != nil {
+		t.Fatalf("Could not submit modal: %v", err.Error())
+	}
+
+	if len(ws.Modals("U0123456")) != 0 {
+		t.Errorf("Expected the modal stack to be cleared")
+	}
+
+	if !eventually(func() bool { return len(ws.DirectMessages("U0123456")) == 1 }) {
+		t.Fatalf("Expected a direct message")
+	}
+
+	textGot, textWant := ws.DirectMessages("U0123456")[0].Text, "Hello Alice"
+	if textGot != textWant {
+		t.Errorf("Unexpected direct message, got: %v, want: %v", textGot, textWant)
+	}
+}
+
+func TestWorkspaceMessageEvent(t *testing.T) {
+	t.Parallel()
+
+	app, ws := createTestWorkspace()
+	defer ws.Close()
+
+	app.RegisterEventHandler("app_mention", func(req *slap.EventRequest) error {
+		req.Ack()
+		user, err := req.Client().GetUserInfo("U0123456")
+		if err != nil {
+			return err
+		}
+		_, _, err = req.Client().PostMessage("C0123456", slack.MsgOptionText("Hi "+user.RealName, false))
+		return err
+	})
+
+	if _, err := ws.SendMessage("U0123456", "C0123456", "Hello <@"+emulator.BotUserID+">"); err != nil {
+		t.Fatalf("Could not send message: %v", err.Error())
+	}
+
+	if !eventually(func() bool { return len(ws.Messages("C0123456")) == 2 }) {
+		t.Fatalf("Expected a reply in the channel")
+	}
+
+	textGot, textWant := ws.Messages("C0123456")[1].Text, "Hi Alice"
+	if textGot != textWant {
+		t.Errorf("Unexpected reply, got: %v, want: %v", textGot, textWant)
+	}
+}
+
+func TestWorkspaceUnknownChannel(t *testing.T) {
+	t.Parallel()
+
+	_, ws := createTestWorkspace()
+	defer ws.Close()
+
+	_, err := ws.RunCommand("U0123456", "C9999999", "/start", "")
+	if err == nil {
+		t.Errorf("Expected an error for an unknown channel")
+	}
+}
+
+func TestWorkspaceModalStackLimit(t *testing.T) {
+	t.Parallel()
+
+	app, ws := createTestWorkspace()
+	defer ws.Close()
+
+	step := slack.ModalViewRequest{
+		Type:       "modal",
+		CallbackID: "step-modal",
+		Title:      slack.NewTextBlockObject("plain_text", "Step", false, false),
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewActionBlock("actions", slack.NewButtonBlockElement("more-button", "more", slack.NewTextBlockObject("plain_text", "More", false, false))),
+		}},
+	}
+	app.RegisterCommand("/start", func(req *slap.CommandRequest) error {
+		req.Ack()
+		_, err := req.Client().OpenView(req.Payload.TriggerID, step)
+		return err
+	})
+	app.RegisterViewSubmission("step-modal", func(req *slap.ViewSubmissionRequest) error {
+		req.AckWithAction(slap.ViewResponseAction{ResponseAction: slap.ViewResponsePush, View: blocks.ResponseView(step)})
+		return nil
+	})
+	errs := make(chan error, 2)
+	app.RegisterBlockAction("more-button", func(req *slap.BlockActionRequest) error {
+		req.Ack()
+		_, err := req.Client().PushView(req.Payload.TriggerID, step)
+		errs <- err
+		_, err = req.Client().OpenView(req.Payload.TriggerID, step)
+		errs <- err
+		return nil
+	})
+
+	if _, err := ws.RunCommand("U0123456", "C0123456", "/start", ""); err != nil {
+		t.Fatalf("Could not run command: %v", err.Error())
+	}
+	if !eventually(func() bool { return len(ws.Modals("U0123456")) == 1 }) {
+		t.Fatalf("Expected a modal to be opened")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := ws.SubmitModal("U0123456", nil); err != nil {
+			t.Fatalf("Could not submit modal: %v", err.Error())
+		}
+	}
+
+	if _, err := ws.SubmitModal("U0123456", nil); err == nil {
+		t.Errorf("Expected an error for pushing a fourth view")
+	}
+	if countGot, countWant := len(ws.Modals("U0123456")), 3; countGot != countWant {
+		t.Errorf("Unexpected number of modals, got: %v, want: %v", countGot, countWant)
+	}
+
+	if _, err := ws.ClickModalButton("U0123456", "more-button"); err != nil {
+		t.Fatalf("Could not click button: %v", err.Error())
+	}
+	if err := <-errs; err == nil || err.Error() != "push_limit_reached" {
+		t.Errorf("Unexpected push error, got: %v, want: push_limit_reached", err)
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("Expected the trigger ID to still be valid, got: %v", err.Error())
+	}
+}
@@ -0,0 +1,47 @@
+package slap
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// Routes a request from a single Slack Request URL to the command,
+// interaction or event handler based on the shape of its body.
+func (app *Application) handleSingleEndpoint(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("content-type"))
+
+	if mediaType == "application/json" || (mediaType == "" && isJSONBody(r)) {
+		app.handleEvent(w, r)
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		app.logger.Error("Failed to parse request form", "error", err.Error())
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	if r.PostForm.Has("payload") {
+		app.handleInteraction(w, r)
+		return
+	}
+
+	if r.PostForm.Has("command") {
+		app.handleCommand(w, r)
+		return
+	}
+
+	http.Error(w, "Unknown request type", http.StatusBadRequest)
+}
+
+func isJSONBody(r *http.Request) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false
+	}
+	r.Body = io.NopCloser(bytes.NewBuffer(body))
+	return len(bytes.TrimSpace(body)) > 0 && bytes.TrimSpace(body)[0] == '{'
+}
@@ -0,0 +1,158 @@
+package slap_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+)
+
+func createSingleEndpointTestApp() (*slap.Application, *http.ServeMux) {
+	router := http.NewServeMux()
+	return slap.New(slap.Config{
+		Router: router,
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		SigningSecret:  "signing-secret",
+		SingleEndpoint: "/slack",
+	}), router
+}
+
+func TestSingleEndpointCommand(t *testing.T) {
+	t.Parallel()
+
+	app, router := createSingleEndpointTestApp()
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.AckWithAction(slap.CommandResponseAction{
+			ResponseType: slap.RespondInChannel,
+			Text:         "Howdy!",
+		})
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/slack", bytes.NewReader(testCommandBody()))
+	r.Header.Add("content-type", "application/x-www-form-urlencoded")
+	addSignatureHeaders(r)
+
+	router.ServeHTTP(w, r)
+	res := w.Result()
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Errorf("Could not read body: %v", err.Error())
+	}
+
+	textGot, textWant := string(body), `{"response_type":"in_channel","text":"Howdy!"}`
+	if textGot != textWant {
+		t.Errorf("Unexpected body text, got: %v, want: %v", textGot, textWant)
+	}
+}
+
+func TestSingleEndpointInteraction(t *testing.T) {
+	t.Parallel()
+
+	app, router := createSingleEndpointTestApp()
+	called := make(chan bool, 1)
+	app.RegisterBlockAction("test-action", func(req *slap.BlockActionRequest) error {
+		called <- true
+		req.Ack()
+		return nil
+	})
+
+	payload, err := getJSONTestData("block_actions_msg_button.json")
+	if err != nil {
+		t.Errorf("Could not get testdata: %v", err.Error())
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/slack", bytes.NewReader([]byte("payload="+string(payload))))
+	r.Header.Add("content-type", "application/x-www-form-urlencoded")
+	addSignatureHeaders(r)
+
+	router.ServeHTTP(w, r)
+	res := w.Result()
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	select {
+	case <-called:
+	default:
+		t.Errorf("Block action handler was not called")
+	}
+}
+
+func TestSingleEndpointEvent(t *testing.T) {
+	t.Parallel()
+
+	_, router := createSingleEndpointTestApp()
+
+	payload, err := getJSONTestData("event_url_verification.json")
+	if err != nil {
+		t.Errorf("Could not get testdata: %v", err.Error())
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/slack", bytes.NewReader(payload))
+	r.Header.Add("content-type", "application/json")
+	addSignatureHeaders(r)
+
+	router.ServeHTTP(w, r)
+	res := w.Result()
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Errorf("Could not read body: %v", err.Error())
+	}
+
+	textGot, textWant := string(body), "ea0bb9129a4ab50da8714fc116b70a0d"
+	if textGot != textWant {
+		t.Errorf("Unexpected body text, got: %v, want: %v", textGot, textWant)
+	}
+}
+
+func TestSingleEndpointUnknown(t *testing.T) {
+	t.Parallel()
+
+	_, router := createSingleEndpointTestApp()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/slack", bytes.NewReader([]byte("hello=world")))
+	r.Header.Add("content-type", "application/x-www-form-urlencoded")
+	addSignatureHeaders(r)
+
+	router.ServeHTTP(w, r)
+	res := w.Result()
+
+	statusGot, statusWant := res.StatusCode, http.StatusBadRequest
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Errorf("Could not read body: %v", err.Error())
+	}
+
+	textGot, textWant := string(body), "Unknown request type\n"
+	if textGot != textWant {
+		t.Errorf("Unexpected body text, got: %v, want: %v", textGot, textWant)
+	}
+}
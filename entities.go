@@ -0,0 +1,174 @@
+package slap
+
+import (
+	"strings"
+)
+
+// The type of an Entity in Slack message text
+type EntityType int
+
+// The supported EntityType values
+const (
+	// Plain text, with Slack's &amp;, &lt; and &gt; escapes decoded
+	TextEntity EntityType = iota
+	// A user mention, e.g. <@U0123456|alice>
+	UserEntity
+	// A channel mention, e.g. <#C0123456|general>
+	ChannelEntity
+	// A user group mention, e.g. <!subteam^S0123456|@admins>
+	UserGroupEntity
+	// A link, e.g. <https://example.com|Example>
+	LinkEntity
+	// A special mention: <!here>, <!channel> or <!everyone>
+	SpecialMentionEntity
+	// A formatted date, e.g. <!date^1392734382^{date}|Feb 18, 2014>
+	DateEntity
+)
+
+func (t EntityType) String() string {
+	switch t {
+	case UserEntity:
+		return "user"
+	case ChannelEntity:
+		return "channel"
+	case UserGroupEntity:
+		return "user group"
+	case LinkEntity:
+		return "link"
+	case SpecialMentionEntity:
+		return "special mention"
+	case DateEntity:
+		return "date"
+	default:
+		return "text"
+	}
+}
+
+// A piece of Slack message or command text
+type Entity struct {
+	// The type of the entity
+	Type EntityType
+	// The ID of a user, channel or user group, or the name of a
+	// special mention: "here", "channel" or "everyone", or the
+	// "timestamp^format" of a date
+	ID string
+	// The URL of a link
+	URL string
+	// The text after the "|" in the escape, or the decoded text of a TextEntity
+	Label string
+	// The entity as it appeared in the original text
+	Raw string
+}
+
+// The text to display for the entity.
+func (e Entity) Text() string {
+	if e.Label != "" {
+		return e.Label
+	}
+	switch e.Type {
+	case UserEntity:
+		return "<@" + e.ID + ">"
+	case ChannelEntity:
+		return "<#" + e.ID + ">"
+	case LinkEntity:
+		return e.URL
+	case SpecialMentionEntity:
+		return "@" + e.ID
+	default:
+		return e.Raw
+	}
+}
+
+// Splits Slack formatted text into plain text and entities such as
+// user, channel and user group mentions and links.
+//
+// See https://api.slack.com/reference/surfaces/formatting#retrieving-messages
+func ParseEntities(text string) []Entity {
+	entities := []Entity{}
+	for len(text) > 0 {
+		start := strings.IndexByte(text, '<')
+		if start == -1 {
+			entities = append(entities, textEntity(text))
+			break
+		}
+		end := strings.IndexByte(text[start:], '>')
+		if end == -1 {
+			entities = append(entities, textEntity(text))
+			break
+		}
+		end += start
+		if start > 0 {
+			entities = append(entities, textEntity(text[:start]))
+		}
+		entities = append(entities, parseEntity(text[start:end+1]))
+		text = text[end+1:]
+	}
+	return entities
+}
+
+// Parses a single escaped entity, e.g. "<@U0123456>". Returns false
+// if raw is not an entity.
+func parseEntityToken(raw string) (Entity, bool) {
+	if len(raw) < 3 || raw[0] != '<' || raw[len(raw)-1] != '>' || strings.Count(raw, ">") != 1 {
+		return Entity{}, false
+	}
+	entity := parseEntity(raw)
+	return entity, entity.Type != TextEntity
+}
+
+func textEntity(raw string) Entity {
+	return Entity{Type: TextEntity, Label: unescapeText(raw), Raw: raw}
+}
+
+func parseEntity(raw string) Entity {
+	inner := raw[1 : len(raw)-1]
+	value, label, _ := strings.Cut(inner, "|")
+	entity := Entity{Label: unescapeText(label), Raw: raw}
+
+	switch {
+	case strings.HasPrefix(value, "@"):
+		entity.Type = UserEntity
+		entity.ID = value[1:]
+	case strings.HasPrefix(value, "#"):
+		entity.Type = ChannelEntity
+		entity.ID = value[1:]
+	case strings.HasPrefix(value, "!subteam^"):
+		entity.Type = UserGroupEntity
+		entity.ID = strings.TrimPrefix(value, "!subteam^")
+	case strings.HasPrefix(value, "!date^"):
+		entity.Type = DateEntity
+		entity.ID = strings.TrimPrefix(value, "!date^")
+	case value == "!here" || value == "!channel" || value == "!everyone":
+		entity.Type = SpecialMentionEntity
+		entity.ID = value[1:]
+	case strings.Contains(value, ":"):
+		entity.Type = LinkEntity
+		entity.URL = unescapeText(value)
+	default:
+		return textEntity(raw)
+	}
+
+	if entity.ID == "" && entity.Type != LinkEntity {
+		return textEntity(raw)
+	}
+	return entity
+}
+
+var textUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+func unescapeText(text string) string {
+	return textUnescaper.Replace(text)
+}
+
+// The entities in the command's text.
+//
+// Users, channels and links are only escaped when "Escape channels,
+// users, and links sent to your app" is enabled for the command.
+func (p *CommandPayload) Entities() []Entity {
+	return ParseEntities(p.Text)
+}
+
+// The entities in the message's text.
+func (e *MessageEvent) Entities() []Entity {
+	return ParseEntities(e.Text)
+}
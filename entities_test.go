@@ -0,0 +1,67 @@
+package slap_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+)
+
+func TestParseEntities(t *testing.T) {
+	t.Parallel()
+
+	text := "Hi <@U0123456|alice> &amp; <@U0654321>, see <#C0123456|general> or <https://example.com?a=1&amp;b=2|the docs> <!subteam^S0123456|@admins> <!here>"
+	got := slap.ParseEntities(text)
+	want := []slap.Entity{
+		{Type: slap.TextEntity, Label: "Hi ", Raw: "Hi "},
+		{Type: slap.UserEntity, ID: "U0123456", Label: "alice", Raw: "<@U0123456|alice>"},
+		{Type: slap.TextEntity, Label: " & ", Raw: " &amp; "},
+		{Type: slap.UserEntity, ID: "U0654321", Raw: "<@U0654321>"},
+		{Type: slap.TextEntity, Label: ", see ", Raw: ", see "},
+		{Type: slap.ChannelEntity, ID: "C0123456", Label: "general", Raw: "<#C0123456|general>"},
+		{Type: slap.TextEntity, Label: " or ", Raw: " or "},
+		{Type: slap.LinkEntity, URL: "https://example.com?a=1&b=2", Label: "the docs", Raw: "<https://example.com?a=1&amp;b=2|the docs>"},
+		{Type: slap.TextEntity, Label: " ", Raw: " "},
+		{Type: slap.UserGroupEntity, ID: "S0123456", Label: "@admins", Raw: "<!subteam^S0123456|@admins>"},
+		{Type: slap.TextEntity, Label: " ", Raw: " "},
+		{Type: slap.SpecialMentionEntity, ID: "here", Raw: "<!here>"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected entities, got: %+v, want: %+v", got, want)
+	}
+}
+
+func TestParseEntitiesUnclosed(t *testing.T) {
+	t.Parallel()
+
+	got := slap.ParseEntities("1 &lt; 2 <@U0123456")
+	want := []slap.Entity{
+		{Type: slap.TextEntity, Label: "1 < 2 <@U0123456", Raw: "1 &lt; 2 <@U0123456"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected entities, got: %+v, want: %+v", got, want)
+	}
+}
+
+func TestEntityText(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"<@U0123456|alice>": "alice",
+		"<@U0123456>":       "<@U0123456>",
+		"<https://a.com>":   "https://a.com",
+		"<!channel>":        "@channel",
+	}
+
+	for raw, want := range tests {
+		entities := slap.ParseEntities(raw)
+		if len(entities) != 1 {
+			t.Fatalf("Unexpected number of entities for %v: %v", raw, len(entities))
+		}
+		if got := entities[0].Text(); got != want {
+			t.Errorf("Unexpected text for %v, got: %v, want: %v", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package slap
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/slack-go/slack"
+)
+
+// The kind of a request
+type RequestKind string
+
+// The RequestKind values
+const (
+	CommandRequestKind         RequestKind = "command"
+	BlockActionRequestKind     RequestKind = "block_action"
+	ViewSubmissionRequestKind  RequestKind = "view_submission"
+	ViewClosedRequestKind      RequestKind = "view_closed"
+	EventRequestKind           RequestKind = "event"
+	MessageShortcutRequestKind RequestKind = "message_shortcut"
+)
+
+// A function to tell the user that a handler returned an error.
+//
+// The payload is a pointer to the request's payload, e.g. a
+// *CommandPayload for CommandRequestKind. Type assert the request,
+// e.g. to a *ViewSubmissionRequest, to acknowledge it with an action.
+// If the request is not acknowledged, Slack receives a 500 response
+// for retryable errors and a 200 otherwise. See IsRetryable.
+type ErrorHandler func(req Request, kind RequestKind, payload any, err error)
+
+// Creates the default ErrorHandler, which sends the message to the
+// user in the most appropriate place for the kind of request:
+//
+//   - Commands, block actions and message shortcuts: an ephemeral
+//     message in the conversation, or a direct message if there is
+//     no conversation or the ephemeral message fails.
+//   - View submissions: the modal is updated to show the message, or
+//     a direct message is sent if the request was already acknowledged.
+//   - Events: nothing is sent.
+//
+// The message of a UserError is sent instead of the generic message,
+// and the FieldErrors of a view submission are shown below its inputs.
+// With a Catalog, messages are translated into the user's locale, using
+// the message as its key. The locale is taken from the payload or the
+// cache, so users.info isn't called while handling an error.
+func DefaultErrorHandler(message string) ErrorHandler {
+	return func(req Request, kind RequestKind, payload any, err error) {
+		text := message
+		if userMessage, ok := UserMessage(err); ok {
+			text = userMessage
+		}
+		if kind != EventRequestKind {
+			if base := baseOf(req); base != nil {
+				text = base.translateKnown(text)
+			} else {
+				text = req.T(text)
+			}
+		}
+
+		switch kind {
+		case EventRequestKind:
+			return
+		case ViewSubmissionRequestKind:
+			submission, ok := req.(*ViewSubmissionRequest)
+			if !ok || submission.ackCalled {
+				break
+			}
+			if fields, ok := ErrorFields(err); ok {
+				submission.AckWithAction(ViewResponseAction{
+					ResponseAction: ViewResponseErrors,
+					Errors:         fields,
+				})
+				return
+			}
+			submission.AckWithAction(ViewResponseAction{
+				ResponseAction: ViewResponseUpdate,
+				View:           errorView(submission.Payload.View, text),
+			})
+			return
+		default:
+			msgerr := req.RespondEphemeral(slack.MsgOptionText(text, false))
+			if msgerr == nil {
+				return
+			}
+			if !errors.Is(msgerr, ErrNoConversation) {
+				requestLogger(req).Warn("Unable to send ephemeral error message", "error", msgerr.Error())
+			}
+		}
+		sendDirectMessage(req, text)
+	}
+}
+
+func sendDirectMessage(req Request, message string) {
+	userID := req.RequestContext().UserID
+	if userID == "" {
+		return
+	}
+	_, _, err := req.Client().PostMessage(userID, slack.MsgOptionText(message, false))
+	if err != nil {
+		requestLogger(req).Error("Unable to send error message to user", "user", userID, "error", err.Error())
+	}
+}
+
+// The logger of a request from an Application, or the default logger.
+func requestLogger(req Request) *slog.Logger {
+	if base := baseOf(req); base != nil {
+		return base.Logger
+	}
+	return slog.Default()
+}
+
+// A modal showing an error message in place of a submitted view.
+func errorView(submitted slack.View, message string) *slack.View {
+	title := submitted.Title
+	if title == nil {
+		title = slack.NewTextBlockObject(slack.PlainTextType, "Error", false, false)
+	}
+	return &slack.View{
+		Type:  slack.VTModal,
+		Title: title,
+		Close: slack.NewTextBlockObject(slack.PlainTextType, "Close", false, false),
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, message, false, false), nil, nil),
+		}},
+	}
+}
+
+// Runs the ErrorHandler for a failed request, then responds to
+// Slack unless the ErrorHandler acknowledged the request: with a 500
+// for retryable errors, or a 200 so Slack doesn't retry.
+func (app *Application) handleError(req Request, kind RequestKind, payload any, err error) {
+	app.errorHandler(req, kind, payload, err)
+	base := baseOf(req)
+	if base.ackCalled {
+		return
+	}
+	if !IsRetryable(err) {
+		req.Ack()
+		return
+	}
+	base.errChannel <- err
+}
@@ -0,0 +1,134 @@
+package slap_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+func TestCustomErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	type failure struct {
+		kind    slap.RequestKind
+		payload any
+		err     error
+	}
+	failures := make(chan failure, 1)
+	handlerErr := errors.New("Failed")
+
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		ErrorHandler: func(req slap.Request, kind slap.RequestKind, payload any, err error) {
+			failures <- failure{kind, payload, err}
+			// Stay silent
+			req.Ack()
+		},
+	})
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		return handlerErr
+	})
+
+	tester := slaptest.New(router, "signing-secret")
+	res := tester.Command(slaptest.Command{Command: "/help", Text: "me"})
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	got := <-failures
+	if got.kind != slap.CommandRequestKind || !errors.Is(got.err, handlerErr) {
+		t.Errorf("Unexpected failure, got: %v %v", got.kind, got.err)
+	}
+
+	payload, ok := got.payload.(*slap.CommandPayload)
+	if !ok || payload.Text != "me" {
+		t.Errorf("Unexpected payload: %v", got.payload)
+	}
+}
+
+func TestDefaultErrorHandlerEphemeral(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		return errors.New("Failed")
+	})
+
+	res := tester.Command(slaptest.Command{Command: "/help"})
+
+	statusGot, statusWant := res.StatusCode, http.StatusInternalServerError
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	call, err := server.WaitForCall("chat.postEphemeral", time.Second)
+	if err != nil {
+		t.Fatalf("Expected an ephemeral message: %v", err.Error())
+	}
+
+	textGot, textWant := call.Params.Get("text"), "An error occurred"
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %v, want: %v", textGot, textWant)
+	}
+}
+
+func TestDefaultErrorHandlerDirectMessage(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+	app.RegisterViewSubmission("form", func(req *slap.ViewSubmissionRequest) error {
+		req.AckWithAction(slap.ViewResponseAction{ResponseAction: slap.ViewResponseClear})
+		return errors.New("Failed")
+	})
+
+	tester.ViewSubmission(slaptest.ViewSubmission{UserID: "U0654321", View: slack.View{CallbackID: "form"}})
+
+	call, err := server.WaitForCall("chat.postMessage", time.Second)
+	if err != nil {
+		t.Fatalf("Expected a direct message: %v", err.Error())
+	}
+
+	channelGot, channelWant := call.Params.Get("channel"), "U0654321"
+	if channelGot != channelWant {
+		t.Errorf("Unexpected channel, got: %v, want: %v", channelGot, channelWant)
+	}
+
+	if calls := server.Calls("chat.postEphemeral"); len(calls) != 0 {
+		t.Errorf("Unexpected ephemeral messages: %v", len(calls))
+	}
+}
+
+func TestDefaultErrorHandlerEventSilent(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+	app.RegisterEventHandler("message", func(req *slap.EventRequest) error {
+		return errors.New("Failed")
+	})
+
+	res := tester.Event(slaptest.Event{Event: map[string]any{"type": "message", "channel": "C0654321", "user": "U0654321"}})
+
+	statusGot, statusWant := res.StatusCode, http.StatusInternalServerError
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	if _, err := server.WaitForCall("chat.postMessage", 50*time.Millisecond); err == nil {
+		t.Errorf("Unexpected message for a failed event")
+	}
+}
@@ -0,0 +1,143 @@
+package slap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The Code of errors that weren't created by slap
+const InternalErrorCode = "internal"
+
+// An error returned by a handler with a message for the user, an
+// error code and a classification.
+//
+// Create one with UserError, FieldErrors, Retryable or Permanent.
+type Error struct {
+	// The message shown to the user instead of Config.ErrorMessage.
+	// Empty to show Config.ErrorMessage.
+	Message string
+	// A short code for metrics and logs, e.g. "missing_permission"
+	Code string
+	// Whether retrying the request may succeed. Slap responds to
+	// Slack with a 500 for retryable errors, so Slack retries
+	// events, and a 200 otherwise.
+	Retryable bool
+	// Errors to show below the input blocks of a submitted view,
+	// by block ID
+	Fields map[string]string
+	// The underlying error, if any
+	Err error
+}
+
+// Creates an error with a message to show the user, e.g.
+//
+//	return slap.UserError("You need admin rights to do that")
+func UserError(message string) *Error {
+	return &Error{Message: message, Code: "user_error"}
+}
+
+// Creates an error with a formatted message to show the user.
+func UserErrorf(format string, args ...any) *Error {
+	return UserError(fmt.Sprintf(format, args...))
+}
+
+// Creates an error that shows messages below the input blocks of a
+// submitted view, by block ID.
+func FieldErrors(fields map[string]string) *Error {
+	return &Error{Fields: fields, Code: "invalid_input"}
+}
+
+// Marks an error as retryable, so Slack receives a 500 response
+// and retries events.
+func Retryable(err error) *Error {
+	return &Error{Err: err, Code: ErrorCode(err), Retryable: true}
+}
+
+// Marks an error as permanent, so Slack receives a 200 response
+// and doesn't retry events.
+func Permanent(err error) *Error {
+	return &Error{Err: err, Code: ErrorCode(err)}
+}
+
+// Sets the error's Code.
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
+// Sets the error's underlying error.
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	if message == "" && len(e.Fields) > 0 {
+		message = fmt.Sprintf("Invalid fields: %v", e.Fields)
+	}
+	if e.Err == nil {
+		return message
+	}
+	if message == "" {
+		return e.Err.Error()
+	}
+	return message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Returns the Code of the first *Error in err's chain, or
+// InternalErrorCode.
+func ErrorCode(err error) string {
+	var e *Error
+	if errors.As(err, &e) && e.Code != "" {
+		return e.Code
+	}
+	return InternalErrorCode
+}
+
+// Whether retrying the request that failed with err may succeed.
+//
+// Errors that weren't created by slap are treated as retryable.
+func IsRetryable(err error) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Retryable
+	}
+	return true
+}
+
+// Returns the message for the user of the first *Error in err's chain
+// that has one.
+func UserMessage(err error) (string, bool) {
+	for err != nil {
+		var e *Error
+		if !errors.As(err, &e) {
+			return "", false
+		}
+		if e.Message != "" {
+			return e.Message, true
+		}
+		err = e.Err
+	}
+	return "", false
+}
+
+// Returns the field errors of the first *Error in err's chain that
+// has them.
+func ErrorFields(err error) (map[string]string, bool) {
+	for err != nil {
+		var e *Error
+		if !errors.As(err, &e) {
+			return nil, false
+		}
+		if len(e.Fields) > 0 {
+			return e.Fields, true
+		}
+		err = e.Err
+	}
+	return nil, false
+}
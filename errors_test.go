@@ -0,0 +1,141 @@
+package slap_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+func TestErrorClassification(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("timeout")
+	userErr := slap.UserError("You need admin rights").WithCode("missing_permission")
+	wrapped := fmt.Errorf("checking permissions: %w", userErr)
+
+	message, ok := slap.UserMessage(wrapped)
+	if !ok || message != "You need admin rights" {
+		t.Errorf("Unexpected user message, got: %v %v", message, ok)
+	}
+
+	var e *slap.Error
+	if !errors.As(wrapped, &e) || e != userErr {
+		t.Errorf("Expected errors.As to find the user error")
+	}
+
+	tests := []struct {
+		err       error
+		code      string
+		retryable bool
+	}{
+		{cause, slap.InternalErrorCode, true},
+		{wrapped, "missing_permission", false},
+		{slap.Retryable(cause).WithCode("upstream_timeout"), "upstream_timeout", true},
+		{slap.Permanent(cause), slap.InternalErrorCode, false},
+		{slap.Retryable(userErr), "missing_permission", true},
+	}
+
+	for _, test := range tests {
+		if got := slap.ErrorCode(test.err); got != test.code {
+			t.Errorf("Unexpected code for %v, got: %v, want: %v", test.err, got, test.code)
+		}
+		if got := slap.IsRetryable(test.err); got != test.retryable {
+			t.Errorf("Unexpected retryable for %v, got: %v, want: %v", test.err, got, test.retryable)
+		}
+	}
+
+	if !errors.Is(slap.Retryable(cause), cause) {
+		t.Errorf("Expected Retryable to wrap its cause")
+	}
+
+	if _, ok := slap.UserMessage(cause); ok {
+		t.Errorf("Unexpected user message for an internal error")
+	}
+}
+
+func TestUserErrorCommand(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		return slap.UserError("You need admin rights")
+	})
+
+	res := tester.Command(slaptest.Command{Command: "/help"})
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	call, err := server.WaitForCall("chat.postEphemeral", time.Second)
+	if err != nil {
+		t.Fatalf("Expected an ephemeral message: %v", err.Error())
+	}
+
+	textGot, textWant := call.Params.Get("text"), "You need admin rights"
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %v, want: %v", textGot, textWant)
+	}
+}
+
+func TestErrorEventStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err    error
+		status int
+	}{
+		"internal":  {errors.New("Failed"), http.StatusInternalServerError},
+		"permanent": {slap.Permanent(errors.New("Failed")), http.StatusOK},
+		"user":      {slap.UserError("Nope"), http.StatusOK},
+		"retryable": {slap.Retryable(errors.New("Failed")), http.StatusInternalServerError},
+	}
+
+	for name, test := range tests {
+		app, tester, server := createConversationTestApp()
+		app.RegisterEventHandler("message", func(req *slap.EventRequest) error {
+			return test.err
+		})
+
+		res := tester.Event(slaptest.Event{Event: map[string]any{"type": "message"}})
+		if res.StatusCode != test.status {
+			t.Errorf("Unexpected status code for %v, got: %v, want: %v", name, res.StatusCode, test.status)
+		}
+		server.Close()
+	}
+}
+
+func TestFieldErrorsViewSubmission(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+	app.RegisterViewSubmission("form", func(req *slap.ViewSubmissionRequest) error {
+		return slap.FieldErrors(map[string]string{"email-block": "Enter a valid email"})
+	})
+
+	res := tester.ViewSubmission(slaptest.ViewSubmission{View: slack.View{CallbackID: "form"}})
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	var action slap.ViewResponseAction
+	if err := json.Unmarshal(res.Body, &action); err != nil {
+		t.Fatalf("Could not decode response action: %v", err.Error())
+	}
+
+	if action.ResponseAction != slap.ViewResponseErrors || action.Errors["email-block"] != "Enter a valid email" {
+		t.Errorf("Unexpected response action: %+v", action)
+	}
+}
@@ -17,18 +17,14 @@ const (
 )
 
 type baseOuterEvent struct {
-	Type           OuterEventType `json:"type"`
-	TeamID         string         `json:"team_id"`
-	ApiAppId       string         `json:"api_app_id"`
-	Authorizations []struct {
-		EnterpriseID string `json:"enterprise_id"`
-		TeamID       string `json:"team_id"`
-		UserID       string `json:"user_id"`
-		IsBot        bool   `json:"is_bot"`
-	} `json:"authorizations"`
-	EventContext string `json:"event_context"`
-	EventID      string `json:"event_id"`
-	EventTime    uint64 `json:"event_time"`
+	Type           OuterEventType  `json:"type"`
+	EnterpriseID   string          `json:"enterprise_id"`
+	TeamID         string          `json:"team_id"`
+	ApiAppId       string          `json:"api_app_id"`
+	Authorizations []Authorization `json:"authorizations"`
+	EventContext   string          `json:"event_context"`
+	EventID        string          `json:"event_id"`
+	EventTime      uint64          `json:"event_time"`
 }
 
 type urlVerificationEvent struct {
@@ -50,6 +46,19 @@ type innerEventType struct {
 	Type string `json:"type"`
 }
 
+// The user of an inner event, which some event types send as an object.
+type innerEventUser struct {
+	User json.RawMessage `json:"user"`
+}
+
+func (u innerEventUser) id() string {
+	var id string
+	if err := json.Unmarshal(u.User, &id); err != nil {
+		return ""
+	}
+	return id
+}
+
 type baseInnerEvent struct {
 	innerEventType
 	User           string `json:"user"`
@@ -127,21 +136,30 @@ func (app *Application) handleEventCallback(w http.ResponseWriter, o outerEvent)
 		return
 	}
 
-	botToken, err := app.botToken(o.TeamID)
-	if err != nil {
-		app.logger.Error("Could not get bot token", "teamID", o.TeamID, "error", err.Error())
-		http.Error(w, "An error occurred", http.StatusInternalServerError)
-		return
-	}
+	var user innerEventUser
+	json.Unmarshal(o.Event, &user)
+	var inner innerEventConversation
+	json.Unmarshal(o.Event, &inner)
 
+	authorize := eventAuthorizeContext(o.baseOuterEvent, user.id())
 	ackChan := make(chan []byte)
 	errChan := make(chan error)
 
 	go func() {
 		req := &EventRequest{
 			baseRequest: baseRequest{
-				Client:     slack.New(botToken),
-				Logger:     app.logger,
+				Logger:    app.logger,
+				app:       app,
+				authorize: authorize,
+				context: RequestContext{
+					EnterpriseID:        authorize.EnterpriseID,
+					TeamID:              authorize.TeamID,
+					IsEnterpriseInstall: authorize.IsEnterpriseInstall,
+					UserID:              user.id(),
+					ChannelID:           inner.channelID(),
+					ThreadTimestamp:     inner.ThreadTimestamp,
+					APIAppID:            o.ApiAppId,
+				},
 				writer:     w,
 				ackCalled:  false,
 				ackChannel: ackChan,
@@ -156,8 +174,8 @@ func (app *Application) handleEventCallback(w http.ResponseWriter, o outerEvent)
 		if err == nil {
 			return
 		}
-		app.logger.Error("An event handler failed", "eventType", innerType.Type, "error", err.Error())
-		errChan <- err
+		app.logger.Error("An event handler failed", "eventType", innerType.Type, "code", ErrorCode(err), "error", err.Error())
+		app.handleError(req, EventRequestKind, &req.Payload, err)
 	}()
 
 	select {
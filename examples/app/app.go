@@ -55,7 +55,7 @@ func main() {
 	app.RegisterBlockAction("start-button", func(req *slap.BlockActionRequest) error {
 		req.Ack()
 
-		_, err := req.Client.OpenView(req.Payload.TriggerID, slack.ModalViewRequest{
+		_, err := req.Client().OpenView(req.Payload.TriggerID, slack.ModalViewRequest{
 			Type:       "modal",
 			CallbackID: "form-modal",
 			Title: &slack.TextBlockObject{
@@ -125,7 +125,7 @@ func main() {
 			ResponseAction: slap.ViewResponseClear,
 		})
 
-		_, _, err := req.Client.PostMessage(req.Payload.User.ID, slack.MsgOptionText("Hello "+fullName.Value, false))
+		_, _, err := req.Client().PostMessage(req.Payload.User.ID, slack.MsgOptionText("Hello "+fullName.Value, false))
 		if err != nil {
 			return err
 		}
@@ -147,7 +147,7 @@ func main() {
 			// conversation it is a part of
 			return nil
 		}
-		_, _, err = req.Client.PostMessage(msg.Channel, slack.MsgOptionText("You wrote: "+msg.Text, false))
+		_, _, err = req.Client().PostMessage(msg.Channel, slack.MsgOptionText("You wrote: "+msg.Text, false))
 		if err != nil {
 			return err
 		}
@@ -2,6 +2,9 @@ module github.com/jacob-ian/slap
 
 go 1.22
 
-require github.com/slack-go/slack v0.12.4
+require (
+	github.com/slack-go/slack v0.12.4
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require github.com/gorilla/websocket v1.4.2 // indirect
@@ -0,0 +1,430 @@
+package slap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The default time a user's locale is cached for.
+const defaultLocaleCacheTTL = time.Hour
+
+// The most users whose locales are cached.
+const maxLocaleCacheSize = 10000
+
+// The plural categories a message can have, from CLDR.
+var pluralCategories = map[string]bool{
+	"zero":  true,
+	"one":   true,
+	"two":   true,
+	"few":   true,
+	"many":  true,
+	"other": true,
+}
+
+// A translated message, either text or plural forms by category.
+type catalogMessage struct {
+	text   string
+	plural map[string]string
+}
+
+// Translated messages for each locale, loaded from JSON files.
+type Catalog struct {
+	defaultLocale string
+	// Messages by normalized locale, then key
+	locales map[string]map[string]catalogMessage
+}
+
+// Loads a catalog from the "<locale>.json" files at the root of a file
+// system, e.g. an embed.FS with "en.json", "fr.json" and "pt-BR.json".
+//
+// Each file is a JSON object of messages. Nested objects are flattened
+// into keys joined with ".", and an object with only plural categories
+// as keys ("zero", "one", "two", "few", "many", "other") is a plural
+// message:
+//
+//	{
+//	  "greeting": "Hello, %v!",
+//	  "tickets": {
+//	    "open": {"one": "%d open ticket", "other": "%d open tickets"}
+//	  }
+//	}
+//
+// Messages missing from a locale fall back to its language, e.g. "fr"
+// for "fr-CA", then to the default locale, which must have a file.
+func NewCatalog(fsys fs.FS, defaultLocale string) (*Catalog, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	catalog := &Catalog{
+		defaultLocale: defaultLocale,
+		locales:       make(map[string]map[string]catalogMessage),
+	}
+	for _, name := range names {
+		blob, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]any
+		if err := json.Unmarshal(blob, &messages); err != nil {
+			return nil, fmt.Errorf("%v: %w", name, err)
+		}
+		flat := make(map[string]catalogMessage)
+		if err := flattenMessages(flat, "", messages); err != nil {
+			return nil, fmt.Errorf("%v: %w", name, err)
+		}
+		catalog.locales[normalizeLocale(strings.TrimSuffix(path.Base(name), ".json"))] = flat
+	}
+	if _, ok := catalog.locales[normalizeLocale(defaultLocale)]; !ok {
+		return nil, fmt.Errorf("missing catalog for the default locale %v", defaultLocale)
+	}
+	return catalog, nil
+}
+
+func flattenMessages(flat map[string]catalogMessage, prefix string, messages map[string]any) error {
+	for key, value := range messages {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case string:
+			flat[key] = catalogMessage{text: v}
+		case map[string]any:
+			if plural, ok := pluralForms(v); ok {
+				flat[key] = catalogMessage{plural: plural}
+				continue
+			}
+			if err := flattenMessages(flat, key, v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %v must be a string or an object", key)
+		}
+	}
+	return nil
+}
+
+// Returns an object's plural forms, if every key is a plural category.
+func pluralForms(object map[string]any) (map[string]string, bool) {
+	if len(object) == 0 {
+		return nil, false
+	}
+	forms := make(map[string]string, len(object))
+	for category, value := range object {
+		text, ok := value.(string)
+		if !ok || !pluralCategories[category] {
+			return nil, false
+		}
+		forms[category] = text
+	}
+	return forms, true
+}
+
+// Returns the locale used when a message or a user's locale is missing.
+func (c *Catalog) DefaultLocale() string {
+	return c.defaultLocale
+}
+
+// Translates a message into a locale, formatting it with fmt.Sprintf
+// when there are args and the message has verbs.
+//
+// For plural messages, the first integer arg chooses the plural form
+// using the locale's plural rules. Returns the key if the message is
+// missing from every fallback locale.
+func (c *Catalog) T(locale string, key string, args ...any) string {
+	text, _ := c.translate(locale, key, args)
+	return text
+}
+
+func (c *Catalog) translate(locale string, key string, args []any) (string, bool) {
+	message, language, ok := c.lookup(locale, key)
+	if !ok {
+		return formatMessage(key, args), false
+	}
+	text := message.text
+	if message.plural != nil {
+		text = message.plural["other"]
+		if n, ok := pluralCount(args); ok {
+			if form, ok := message.plural["zero"]; ok && n == 0 {
+				text = form
+			} else if form, ok := message.plural[pluralCategory(language, n)]; ok {
+				text = form
+			}
+		}
+	}
+	return formatMessage(text, args), true
+}
+
+// Finds a message in a locale, its language or the default locale.
+// Returns the language of the catalog the message was found in.
+func (c *Catalog) lookup(locale string, key string) (catalogMessage, string, bool) {
+	for _, candidate := range localeFallbacks(locale, c.defaultLocale) {
+		messages, ok := c.locales[candidate]
+		if !ok {
+			continue
+		}
+		if message, ok := messages[key]; ok {
+			language, _, _ := strings.Cut(candidate, "-")
+			return message, language, true
+		}
+	}
+	return catalogMessage{}, "", false
+}
+
+// The normalized locales to look for a message in, in order.
+func localeFallbacks(locale string, defaultLocale string) []string {
+	var fallbacks []string
+	for _, l := range []string{locale, defaultLocale} {
+		l = normalizeLocale(l)
+		if l == "" {
+			continue
+		}
+		fallbacks = append(fallbacks, l)
+		if language, _, ok := strings.Cut(l, "-"); ok {
+			fallbacks = append(fallbacks, language)
+		}
+	}
+	return fallbacks
+}
+
+// Normalizes a locale for comparison, e.g. "pt_BR" to "pt-br".
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// Formats a message with fmt.Sprintf. Messages without verbs are
+// returned as they are, e.g. a plural form like "No open tickets".
+func formatMessage(text string, args []any) string {
+	if len(args) == 0 || !strings.Contains(text, "%") {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// The first integer arg, which chooses a plural form.
+func pluralCount(args []any) (int64, bool) {
+	for _, arg := range args {
+		switch n := arg.(type) {
+		case int:
+			return int64(n), true
+		case int8:
+			return int64(n), true
+		case int16:
+			return int64(n), true
+		case int32:
+			return int64(n), true
+		case int64:
+			return n, true
+		case uint:
+			return int64(n), true
+		case uint8:
+			return int64(n), true
+		case uint16:
+			return int64(n), true
+		case uint32:
+			return int64(n), true
+		case uint64:
+			return int64(n), true
+		}
+	}
+	return 0, false
+}
+
+// The CLDR plural category of an integer in a language.
+func pluralCategory(language string, n int64) string {
+	if n < 0 {
+		n = -n
+	}
+	mod10, mod100 := n%10, n%100
+	switch language {
+	case "ja", "zh", "ko", "th", "vi", "id", "ms", "tr":
+		return "other"
+	case "fr", "pt", "hi", "fa":
+		if n <= 1 {
+			return "one"
+		}
+	case "ru", "uk", "be":
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		}
+		return "many"
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		}
+		return "many"
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
+
+// A cache of users' locales from users.info.
+type localeCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[string]localeCacheEntry
+}
+
+type localeCacheEntry struct {
+	locale    string
+	expiresAt time.Time
+}
+
+func newLocaleCache(ttl time.Duration) *localeCache {
+	return &localeCache{
+		ttl:     ttl,
+		size:    maxLocaleCacheSize,
+		entries: make(map[string]localeCacheEntry),
+	}
+}
+
+func (c *localeCache) get(userID string, now time.Time) (string, bool) {
+	if c.ttl <= 0 {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[userID]
+	if !ok || now.After(entry.expiresAt) {
+		delete(c.entries, userID)
+		return "", false
+	}
+	return entry.locale, true
+}
+
+func (c *localeCache) set(userID string, locale string, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[userID]; !ok && len(c.entries) >= c.size {
+		c.evict(now)
+	}
+	c.entries[userID] = localeCacheEntry{locale: locale, expiresAt: now.Add(c.ttl)}
+}
+
+// Removes expired locales, then other locales while the cache is
+// more than three quarters full.
+func (c *localeCache) evict(now time.Time) {
+	for userID, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, userID)
+		}
+	}
+	for userID := range c.entries {
+		if len(c.entries) <= c.size*3/4 {
+			return
+		}
+		delete(c.entries, userID)
+	}
+}
+
+// Returns the locale of the user who made the request, resolving it
+// on first use: from the payload when the app has enabled
+// include_locale, otherwise from users.info, which needs the
+// users:read scope. Locales from users.info are cached.
+//
+// Returns the Catalog's default locale if the locale is unknown.
+func (req *baseRequest) Locale() string {
+	req.localeOnce.Do(func() {
+		req.locale = req.app.resolveLocale(req)
+	})
+	return req.locale
+}
+
+// Translates a message into the locale of the user who made the
+// request with the Catalog set in Config. See Catalog.T.
+//
+// Without a Catalog, the key is formatted with the args.
+func (req *baseRequest) T(key string, args ...any) string {
+	if req.app.catalog == nil {
+		return formatMessage(key, args)
+	}
+	return req.translate(req.Locale(), key, args)
+}
+
+// Translates a message like T, but only into a locale from the
+// payload or the cache, so that it never waits for users.info,
+// e.g. for an error message.
+func (req *baseRequest) translateKnown(key string, args ...any) string {
+	if req.app.catalog == nil {
+		return formatMessage(key, args)
+	}
+	locale, ok := req.app.knownLocale(req, time.Now())
+	if !ok {
+		locale = req.app.catalog.defaultLocale
+	}
+	return req.translate(locale, key, args)
+}
+
+func (req *baseRequest) translate(locale string, key string, args []any) string {
+	text, ok := req.app.catalog.translate(locale, key, args)
+	if !ok {
+		req.Logger.Debug("Missing translation", "locale", locale, "key", key)
+	}
+	return text
+}
+
+// The locale of the user who made the request from the payload or
+// the cache.
+func (app *Application) knownLocale(req *baseRequest, now time.Time) (string, bool) {
+	userID := req.context.UserID
+	if req.context.Locale != "" {
+		if userID != "" {
+			app.locales.set(userID, req.context.Locale, now)
+		}
+		return req.context.Locale, true
+	}
+	if userID == "" {
+		return "", false
+	}
+	return app.locales.get(userID, now)
+}
+
+func (app *Application) resolveLocale(req *baseRequest) string {
+	defaultLocale := ""
+	if app.catalog != nil {
+		defaultLocale = app.catalog.defaultLocale
+	}
+	userID := req.context.UserID
+	now := time.Now()
+	if locale, ok := app.knownLocale(req, now); ok {
+		return locale
+	}
+	if userID == "" {
+		return defaultLocale
+	}
+	user, err := req.Client().GetUserInfo(userID)
+	if err != nil {
+		req.Logger.Warn("Unable to get the user's locale", "user", userID, "error", err.Error())
+		return defaultLocale
+	}
+	if user.Locale == "" {
+		return defaultLocale
+	}
+	app.locales.set(userID, user.Locale, now)
+	return user.Locale
+}
@@ -0,0 +1,207 @@
+package slap_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+var testCatalogFS = fstest.MapFS{
+	"en.json": {Data: []byte(`{
+  "greeting": "Hello, %v!",
+  "errors": {"generic": "Something went wrong"},
+  "tickets": {"zero": "No open tickets", "one": "%d open ticket", "other": "%d open tickets"}
+}`)},
+	"fr.json": {Data: []byte(`{
+  "greeting": "Bonjour, %v !",
+  "errors": {"generic": "Une erreur est survenue"},
+  "tickets": {"one": "%d ticket ouvert", "other": "%d tickets ouverts"}
+}`)},
+	"pt.json": {Data: []byte(`{
+  "tickets": {"one": "%d ticket aberto", "other": "%d tickets abertos"}
+}`)},
+	"ru.json": {Data: []byte(`{
+  "tickets": {"one": "%d открытый тикет", "few": "%d открытых тикета", "many": "%d открытых тикетов"}
+}`)},
+}
+
+func createTestCatalog(t *testing.T) *slap.Catalog {
+	catalog, err := slap.NewCatalog(testCatalogFS, "en")
+	if err != nil {
+		t.Fatalf("Could not load catalog: %v", err.Error())
+	}
+	return catalog
+}
+
+func TestCatalog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		locale string
+		key    string
+		args   []any
+		want   string
+	}{
+		{"en-US", "greeting", []any{"Ada"}, "Hello, Ada!"},
+		{"fr-CA", "greeting", []any{"Ada"}, "Bonjour, Ada !"},
+		{"fr_FR", "errors.generic", nil, "Une erreur est survenue"},
+		{"de-DE", "greeting", []any{"Ada"}, "Hello, Ada!"},
+		{"ru-RU", "greeting", []any{"Ada"}, "Hello, Ada!"},
+		{"en-US", "missing.key", nil, "missing.key"},
+		{"en-US", "tickets", []any{0}, "No open tickets"},
+		{"en-US", "tickets", []any{1}, "1 open ticket"},
+		{"en-US", "tickets", []any{2}, "2 open tickets"},
+		{"fr-FR", "tickets", []any{0}, "0 ticket ouvert"},
+		{"fr-FR", "tickets", []any{2}, "2 tickets ouverts"},
+		{"pt-BR", "tickets", []any{0}, "0 ticket aberto"},
+		{"pt-BR", "tickets", []any{2}, "2 tickets abertos"},
+		{"ru-RU", "tickets", []any{21}, "21 открытый тикет"},
+		{"ru-RU", "tickets", []any{3}, "3 открытых тикета"},
+		{"ru-RU", "tickets", []any{11}, "11 открытых тикетов"},
+	}
+
+	catalog := createTestCatalog(t)
+	for _, test := range tests {
+		got := catalog.T(test.locale, test.key, test.args...)
+		if got != test.want {
+			t.Errorf("Unexpected translation of %v in %v, got: %v, want: %v", test.key, test.locale, got, test.want)
+		}
+	}
+}
+
+func TestCatalogMissingDefaultLocale(t *testing.T) {
+	t.Parallel()
+
+	_, err := slap.NewCatalog(testCatalogFS, "de")
+	if err == nil {
+		t.Errorf("Expected an error for a missing default locale")
+	}
+}
+
+func createLocaleTestApp(t *testing.T) (*slap.Application, *slaptest.Tester, *slaptest.Server) {
+	server := slaptest.NewServer()
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		APIURL:       server.APIURL(),
+		Catalog:      createTestCatalog(t),
+		ErrorMessage: "errors.generic",
+	})
+	return app, slaptest.New(router, "signing-secret"), server
+}
+
+func TestRequestLocaleFromPayload(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createLocaleTestApp(t)
+	defer server.Close()
+	texts := make(chan string, 1)
+	app.RegisterBlockAction("greet", func(req *slap.BlockActionRequest) error {
+		req.Ack()
+		texts <- req.T("greeting", "Ada")
+		return nil
+	})
+
+	tester.BlockAction(slaptest.BlockAction{Action: slack.BlockAction{ActionID: "greet"}, Locale: "fr-FR"})
+
+	textGot, textWant := <-texts, "Bonjour, Ada !"
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %v, want: %v", textGot, textWant)
+	}
+	if calls := server.Calls("users.info"); len(calls) != 0 {
+		t.Errorf("Expected no users.info calls, got: %v", len(calls))
+	}
+}
+
+func TestRequestLocaleFromUsersInfo(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createLocaleTestApp(t)
+	defer server.Close()
+	server.Handle("users.info", func(call slaptest.Call) any {
+		return map[string]any{
+			"ok":   true,
+			"user": map[string]any{"id": call.Params.Get("user"), "locale": "fr-FR"},
+		}
+	})
+	locales := make(chan string, 2)
+	app.RegisterCommand("/tickets", func(req *slap.CommandRequest) error {
+		req.Ack()
+		locales <- req.Locale()
+		return nil
+	})
+
+	for i := 0; i < 2; i++ {
+		tester.Command(slaptest.Command{Command: "/tickets"})
+		localeGot, localeWant := <-locales, "fr-FR"
+		if localeGot != localeWant {
+			t.Errorf("Unexpected locale, got: %v, want: %v", localeGot, localeWant)
+		}
+	}
+
+	callsGot, callsWant := len(server.Calls("users.info")), 1
+	if callsGot != callsWant {
+		t.Errorf("Expected the locale to be cached, got: %v users.info calls, want: %v", callsGot, callsWant)
+	}
+}
+
+func TestLocalizedErrorMessage(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createLocaleTestApp(t)
+	defer server.Close()
+	app.RegisterBlockAction("fail", func(req *slap.BlockActionRequest) error {
+		return errors.New("failed")
+	})
+
+	tester.BlockAction(slaptest.BlockAction{Action: slack.BlockAction{ActionID: "fail"}, Locale: "fr-FR"})
+
+	call, err := server.WaitForCall("chat.postEphemeral", time.Second)
+	if err != nil {
+		t.Fatalf("Expected an ephemeral message: %v", err.Error())
+	}
+	textGot, textWant := call.Params.Get("text"), "Une erreur est survenue"
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %v, want: %v", textGot, textWant)
+	}
+}
+
+func TestErrorMessageWithoutUsersInfo(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createLocaleTestApp(t)
+	defer server.Close()
+	server.Handle("users.info", func(call slaptest.Call) any {
+		return map[string]any{
+			"ok":   true,
+			"user": map[string]any{"id": call.Params.Get("user"), "locale": "fr-FR"},
+		}
+	})
+	app.RegisterBlockAction("fail", func(req *slap.BlockActionRequest) error {
+		return errors.New("failed")
+	})
+
+	tester.BlockAction(slaptest.BlockAction{Action: slack.BlockAction{ActionID: "fail"}})
+
+	call, err := server.WaitForCall("chat.postEphemeral", time.Second)
+	if err != nil {
+		t.Fatalf("Expected an ephemeral message: %v", err.Error())
+	}
+	textGot, textWant := call.Params.Get("text"), "Something went wrong"
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %v, want: %v", textGot, textWant)
+	}
+	if calls := server.Calls("users.info"); len(calls) != 0 {
+		t.Errorf("Expected no users.info calls, got: %v", len(calls))
+	}
+}
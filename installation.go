@@ -0,0 +1,272 @@
+package slap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Returned by an InstallationStore when no installation exists.
+var ErrInstallationNotFound = errors.New("Installation not found")
+
+// The result of installing the Slack App to a workspace.
+type Installation struct {
+	// Your Slack App's unique identifier.
+	AppID string `json:"app_id"`
+	// The Enterprise ID if the workspace belongs to an Enterprise Grid.
+	EnterpriseID string `json:"enterprise_id,omitempty"`
+	// The name of the Enterprise Grid.
+	EnterpriseName string `json:"enterprise_name,omitempty"`
+	// The Team ID of the workspace.
+	TeamID string `json:"team_id"`
+	// The name of the workspace.
+	TeamName string `json:"team_name"`
+	// Whether the app was installed to the entire Enterprise Grid.
+	IsEnterpriseInstall bool `json:"is_enterprise_install"`
+	// The user ID of the app's bot user.
+	BotUserID string `json:"bot_user_id"`
+	// The bot token for the workspace.
+	BotToken string `json:"bot_token"`
+	// The comma-separated scopes granted to the bot token.
+	BotScopes string `json:"bot_scopes"`
+	// The refresh token for the bot token if token rotation is enabled.
+	BotRefreshToken string `json:"bot_refresh_token,omitempty"`
+	// When the bot token expires if token rotation is enabled.
+	BotTokenExpiresAt time.Time `json:"bot_token_expires_at"`
+	// The ID of the user who installed the app.
+	UserID string `json:"user_id"`
+	// The installing user's token, if user scopes were requested.
+	UserToken string `json:"user_token,omitempty"`
+	// The comma-separated scopes granted to the user token.
+	UserScopes string `json:"user_scopes,omitempty"`
+	// The refresh token for the user token if token rotation is enabled.
+	UserRefreshToken string `json:"user_refresh_token,omitempty"`
+	// When the user token expires if token rotation is enabled.
+	UserTokenExpiresAt time.Time `json:"user_token_expires_at"`
+	// When the installation was completed.
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// Identifies an installation.
+//
+// Org-wide installations are identified by their Enterprise ID,
+// all others by their Team ID. When UserID is set, the query
+// identifies the installation authorized by that user.
+type InstallationQuery struct {
+	EnterpriseID        string
+	TeamID              string
+	IsEnterpriseInstall bool
+	UserID              string
+}
+
+func (q InstallationQuery) key() string {
+	key := q.TeamID
+	if q.IsEnterpriseInstall {
+		key = q.EnterpriseID
+	}
+	if q.UserID != "" {
+		key += "-" + q.UserID
+	}
+	return key
+}
+
+// The query that finds this installation for the workspace or organisation.
+func (i Installation) Query() InstallationQuery {
+	return InstallationQuery{
+		EnterpriseID:        i.EnterpriseID,
+		TeamID:              i.TeamID,
+		IsEnterpriseInstall: i.IsEnterpriseInstall,
+	}
+}
+
+// Persists Slack App installations.
+//
+// Implementations must be safe for concurrent use.
+type InstallationStore interface {
+	// Saves an installation, replacing any existing
+	// installation for the same workspace or organisation.
+	//
+	// If the installation has a user token, it must also be
+	// saved for queries with the installation's UserID. The
+	// workspace's existing user token is only replaced by one
+	// from the same user, see MergeInstallation.
+	Save(installation Installation) error
+	// Finds the installation matching the query exactly.
+	//
+	// Returns ErrInstallationNotFound if there is no installation.
+	Find(query InstallationQuery) (*Installation, error)
+}
+
+// An InstallationStore held in memory.
+type MemoryInstallationStore struct {
+	mu            sync.RWMutex
+	installations map[string]Installation
+}
+
+// Creates an empty MemoryInstallationStore.
+func NewMemoryInstallationStore() *MemoryInstallationStore {
+	return &MemoryInstallationStore{
+		installations: make(map[string]Installation),
+	}
+}
+
+// Saves an installation in memory.
+func (s *MemoryInstallationStore) Save(installation Installation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, query := range installationQueries(installation) {
+		saved := installation
+		if existing, ok := s.installations[query.key()]; ok && i == 0 {
+			saved = MergeInstallation(&existing, installation)
+		}
+		s.installations[query.key()] = saved
+	}
+	return nil
+}
+
+// Finds an installation in memory.
+func (s *MemoryInstallationStore) Find(query InstallationQuery) (*Installation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	installation, ok := s.installations[query.key()]
+	if !ok {
+		return nil, ErrInstallationNotFound
+	}
+	return &installation, nil
+}
+
+// An InstallationStore that writes each installation
+// to a JSON file in a directory.
+type FileInstallationStore struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// Creates a FileInstallationStore in dir, creating the directory if needed.
+func NewFileInstallationStore(dir string) (*FileInstallationStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileInstallationStore{dir: dir}, nil
+}
+
+func (s *FileInstallationStore) path(query InstallationQuery) (string, error) {
+	key := query.key()
+	if key == "" || strings.HasPrefix(key, "-") || strings.ContainsAny(key, `/\.`) {
+		return "", fmt.Errorf("Invalid installation ID %q", key)
+	}
+	return filepath.Join(s.dir, key+".json"), nil
+}
+
+// Saves an installation to "<dir>/<ID>.json", where ID is the
+// Enterprise ID for org-wide installs and the Team ID otherwise.
+//
+// Installations with a user token are also saved to "<dir>/<ID>-<UserID>.json".
+func (s *FileInstallationStore) Save(installation Installation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, query := range installationQueries(installation) {
+		path, err := s.path(query)
+		if err != nil {
+			return err
+		}
+		saved := installation
+		if i == 0 {
+			existing, err := s.read(path)
+			if err != nil && !errors.Is(err, ErrInstallationNotFound) {
+				return err
+			}
+			saved = MergeInstallation(existing, installation)
+		}
+		bytes, err := json.MarshalIndent(saved, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err = s.write(path, bytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *FileInstallationStore) write(path string, bytes []byte) error {
+	tmp, err := os.CreateTemp(s.dir, ".installation-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(bytes); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Finds an installation in "<dir>/<ID>.json".
+func (s *FileInstallationStore) Find(query InstallationQuery) (*Installation, error) {
+	path, err := s.path(query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.read(path)
+}
+
+func (s *FileInstallationStore) read(path string) (*Installation, error) {
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrInstallationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var installation Installation
+	if err = json.Unmarshal(bytes, &installation); err != nil {
+		return nil, err
+	}
+	return &installation, nil
+}
+
+// Returns the installation to save for a workspace or organisation
+// that has an existing installation, for InstallationStore
+// implementations.
+//
+// The existing user token is kept unless the installation was
+// authorized by the same user, so that a user authorizing the app
+// doesn't replace the token of the user who installed it.
+func MergeInstallation(existing *Installation, installation Installation) Installation {
+	if existing == nil || existing.UserToken == "" || existing.UserID == installation.UserID {
+		return installation
+	}
+	installation.UserID = existing.UserID
+	installation.UserToken = existing.UserToken
+	installation.UserScopes = existing.UserScopes
+	installation.UserRefreshToken = existing.UserRefreshToken
+	installation.UserTokenExpiresAt = existing.UserTokenExpiresAt
+	return installation
+}
+
+// The queries an installation must be saved under. The first is the
+// workspace or organisation's query.
+func installationQueries(installation Installation) []InstallationQuery {
+	query := installation.Query()
+	queries := []InstallationQuery{query}
+	if installation.UserID != "" && installation.UserToken != "" {
+		query.UserID = installation.UserID
+		queries = append(queries, query)
+	}
+	return queries
+}
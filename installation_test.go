@@ -0,0 +1,103 @@
+package slap_test
+
+import (
+	"testing"
+
+	"github.com/jacob-ian/slap"
+)
+
+func TestFileInstallationStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := slap.NewFileInstallationStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Could not create store: %v", err.Error())
+	}
+
+	_, err = store.Find(slap.InstallationQuery{TeamID: "T0123456"})
+	if err != slap.ErrInstallationNotFound {
+		t.Errorf("Unexpected error, got: %v, want: %v", err, slap.ErrInstallationNotFound)
+	}
+
+	err = store.Save(slap.Installation{TeamID: "T0123456", BotToken: "xoxb-test"})
+	if err != nil {
+		t.Fatalf("Could not save installation: %v", err.Error())
+	}
+
+	installation, err := store.Find(slap.InstallationQuery{TeamID: "T0123456"})
+	if err != nil {
+		t.Fatalf("Could not find installation: %v", err.Error())
+	}
+
+	tokenGot, tokenWant := installation.BotToken, "xoxb-test"
+	if tokenGot != tokenWant {
+		t.Errorf("Unexpected bot token, got: %v, want: %v", tokenGot, tokenWant)
+	}
+}
+
+func TestFileInstallationStoreInvalidTeamID(t *testing.T) {
+	t.Parallel()
+
+	store, err := slap.NewFileInstallationStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Could not create store: %v", err.Error())
+	}
+
+	err = store.Save(slap.Installation{TeamID: "../T0123456"})
+	if err == nil {
+		t.Errorf("Expected an error for an invalid team ID")
+	}
+}
+
+func TestInstallationStoreKeepsWorkspaceUserToken(t *testing.T) {
+	t.Parallel()
+
+	fileStore, err := slap.NewFileInstallationStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Could not create store: %v", err.Error())
+	}
+	stores := map[string]slap.InstallationStore{
+		"memory": slap.NewMemoryInstallationStore(),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		installs := []slap.Installation{
+			{TeamID: "T0123456", BotToken: "xoxb-1", UserID: "U0000001", UserToken: "xoxp-1"},
+			{TeamID: "T0123456", BotToken: "xoxb-2", UserID: "U0000002", UserToken: "xoxp-2"},
+		}
+		for _, installation := range installs {
+			if err := store.Save(installation); err != nil {
+				t.Fatalf("Could not save installation to the %v store: %v", name, err.Error())
+			}
+		}
+
+		workspace, err := store.Find(slap.InstallationQuery{TeamID: "T0123456"})
+		if err != nil {
+			t.Fatalf("Could not find installation in the %v store: %v", name, err.Error())
+		}
+		if workspace.BotToken != "xoxb-2" || workspace.UserID != "U0000001" || workspace.UserToken != "xoxp-1" {
+			t.Errorf("Unexpected workspace installation in the %v store: %+v", name, workspace)
+		}
+
+		user, err := store.Find(slap.InstallationQuery{TeamID: "T0123456", UserID: "U0000002"})
+		if err != nil {
+			t.Fatalf("Could not find user installation in the %v store: %v", name, err.Error())
+		}
+		if user.UserToken != "xoxp-2" {
+			t.Errorf("Unexpected user token in the %v store, got: %v, want: xoxp-2", name, user.UserToken)
+		}
+	}
+}
+
+func TestMergeInstallationSameUser(t *testing.T) {
+	t.Parallel()
+
+	existing := slap.Installation{TeamID: "T0123456", UserID: "U0000001", UserToken: "xoxp-old"}
+	merged := slap.MergeInstallation(&existing, slap.Installation{TeamID: "T0123456", UserID: "U0000001", UserToken: "xoxp-new"})
+
+	tokenGot, tokenWant := merged.UserToken, "xoxp-new"
+	if tokenGot != tokenWant {
+		t.Errorf("Unexpected user token, got: %v, want: %v", tokenGot, tokenWant)
+	}
+}
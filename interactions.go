@@ -15,15 +15,57 @@ type interactionPayload struct {
 		ID     string `json:"id"`
 		Domain string `json:"domain"`
 	} `json:"team"`
-	User struct {
+	Enterprise *struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"enterprise,omitempty"`
+	IsEnterpriseInstall bool `json:"is_enterprise_install"`
+	User                struct {
 		ID       string `json:"id"`
 		Username string `json:"username"`
 		TeamID   string `json:"team_id"`
+		Locale   string `json:"locale"`
 	} `json:"user"`
+	// Sent instead of user.locale by some interactions
+	Locale    string `json:"locale"`
 	TriggerID string `json:"trigger_id"`
 	ApiAppId  string `json:"api_app_id"`
 }
 
+func (p *interactionPayload) authorizeContext() AuthorizeContext {
+	ctx := AuthorizeContext{
+		TeamID:              p.Team.ID,
+		IsEnterpriseInstall: p.IsEnterpriseInstall,
+		UserID:              p.User.ID,
+	}
+	if ctx.TeamID == "" {
+		// Org-wide installs may send interactions without a team
+		ctx.TeamID = p.User.TeamID
+	}
+	if p.Enterprise != nil {
+		ctx.EnterpriseID = p.Enterprise.ID
+	}
+	return ctx
+}
+
+// The context shared by every kind of interaction.
+func (p *interactionPayload) requestContext() RequestContext {
+	authorize := p.authorizeContext()
+	locale := p.User.Locale
+	if locale == "" {
+		locale = p.Locale
+	}
+	return RequestContext{
+		EnterpriseID:        authorize.EnterpriseID,
+		TeamID:              authorize.TeamID,
+		IsEnterpriseInstall: authorize.IsEnterpriseInstall,
+		UserID:              authorize.UserID,
+		APIAppID:            p.ApiAppId,
+		TriggerID:           p.TriggerID,
+		Locale:              locale,
+	}
+}
+
 func (app *Application) handleInteraction(w http.ResponseWriter, r *http.Request) {
 	blob := []byte(r.FormValue("payload"))
 
@@ -41,6 +83,12 @@ func (app *Application) handleInteraction(w http.ResponseWriter, r *http.Request
 	} else if payloadType.Type == "block_actions" {
 		app.handleBlockActions(w, blob)
 		return
+	} else if payloadType.Type == "view_closed" {
+		app.handleViewClosed(w, blob)
+		return
+	} else if payloadType.Type == "message_action" {
+		app.handleMessageShortcut(w, blob)
+		return
 	} else {
 		http.Error(w, "Unknown interaction type", http.StatusInternalServerError)
 		return
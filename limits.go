@@ -0,0 +1,44 @@
+package slap
+
+import (
+	"github.com/jacob-ian/slap/blocks"
+)
+
+// How response actions are checked against Block Kit limits, such
+// as 50 blocks per message and 24 characters per modal title
+type BlockLimitMode int
+
+// The BlockLimitMode values
+const (
+	// Logs a warning for each limit that is exceeded, then sends the
+	// response anyway. Suitable for production.
+	WarnBlockLimits BlockLimitMode = iota
+	// Logs an error for each limit that is exceeded, and responds to
+	// Slack with a 500 instead of sending the response. Suitable for
+	// development.
+	EnforceBlockLimits
+	// Doesn't check response actions.
+	IgnoreBlockLimits
+)
+
+// Logs the Block Kit limits exceeded by a response action. Returns
+// an error if the response should not be sent.
+func (req *baseRequest) checkBlockLimits(action string, err error) error {
+	if err == nil {
+		return nil
+	}
+	switch req.app.blockLimits {
+	case IgnoreBlockLimits:
+		return nil
+	case EnforceBlockLimits:
+		for _, e := range blocks.Errors(err) {
+			req.Logger.Error("Response action exceeds a Block Kit limit", "action", action, "path", e.Path, "error", e.Message)
+		}
+		return err
+	default:
+		for _, e := range blocks.Errors(err) {
+			req.Logger.Warn("Response action exceeds a Block Kit limit", "action", action, "path", e.Path, "error", e.Message)
+		}
+		return nil
+	}
+}
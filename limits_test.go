@@ -0,0 +1,102 @@
+package slap_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+func createBlockLimitsTestApp(mode slap.BlockLimitMode, logs *bytes.Buffer) (*slap.Application, *slaptest.Tester) {
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		Logger:      slog.New(slog.NewTextHandler(logs, nil)),
+		BlockLimits: mode,
+	})
+	return app, slaptest.New(router, "signing-secret")
+}
+
+// A command response with more blocks than a message allows
+func tooManyBlocks() slap.CommandResponseAction {
+	blocks := make([]slack.Block, 51)
+	for i := range blocks {
+		blocks[i] = slack.NewDividerBlock()
+	}
+	return slap.CommandResponseAction{ResponseType: slap.RespondEphemeral, Blocks: blocks}
+}
+
+func TestBlockLimitsWarn(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	app, tester := createBlockLimitsTestApp(slap.WarnBlockLimits, &logs)
+	app.RegisterCommand("/big", func(req *slap.CommandRequest) error {
+		req.AckWithAction(tooManyBlocks())
+		return nil
+	})
+
+	res := tester.Command(slaptest.Command{Command: "/big"})
+	statusGot, statusWant := res.StatusCode, 200
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+	if !strings.Contains(logs.String(), "level=WARN") || !strings.Contains(logs.String(), "path=blocks") {
+		t.Errorf("Expected a warning with the path, got: %v", logs.String())
+	}
+}
+
+func TestBlockLimitsEnforce(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	app, tester := createBlockLimitsTestApp(slap.EnforceBlockLimits, &logs)
+	app.RegisterCommand("/big", func(req *slap.CommandRequest) error {
+		req.AckWithAction(tooManyBlocks())
+		return nil
+	})
+
+	res := tester.Command(slaptest.Command{Command: "/big"})
+	statusGot, statusWant := res.StatusCode, 500
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+	if !strings.Contains(logs.String(), "level=ERROR") || !strings.Contains(logs.String(), "path=blocks") {
+		t.Errorf("Expected an error with the path, got: %v", logs.String())
+	}
+}
+
+func TestBlockLimitsEnforceView(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	app, tester := createBlockLimitsTestApp(slap.EnforceBlockLimits, &logs)
+	app.RegisterViewSubmission("form", func(req *slap.ViewSubmissionRequest) error {
+		req.AckWithAction(slap.ViewResponseAction{
+			ResponseAction: slap.ViewResponseUpdate,
+			View: &slack.View{
+				Type:  slack.VTModal,
+				Title: slack.NewTextBlockObject(slack.PlainTextType, "A title that is far too long", false, false),
+			},
+		})
+		return nil
+	})
+
+	res := tester.ViewSubmission(slaptest.ViewSubmission{View: slack.View{CallbackID: "form"}})
+	statusGot, statusWant := res.StatusCode, 500
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+	if !strings.Contains(logs.String(), "path=view.title.text") {
+		t.Errorf("Expected an error with the path, got: %v", logs.String())
+	}
+}
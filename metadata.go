@@ -0,0 +1,266 @@
+package slap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Slack's limit on the length of a view's private_metadata
+const maxPrivateMetadataLength = 3000
+
+// Metadata at least this long is compressed
+const compressMetadataLength = 256
+
+// How long a MemoryMetadataStore keeps metadata
+const defaultMetadataTTL = 24 * time.Hour
+
+// The ways metadata is encoded
+const (
+	metadataJSON       = "j"
+	metadataCompressed = "z"
+	metadataStored     = "s"
+)
+
+var (
+	// The private_metadata was not created by EncodeMetadata with
+	// the same secret, or has been modified
+	ErrInvalidMetadata = errors.New("Invalid private metadata")
+	// The encoded value is too long for private_metadata and there
+	// is no MetadataStore to keep it in
+	ErrMetadataTooLarge = errors.New("Private metadata is too large")
+	// The value of the private_metadata could not be found in the
+	// MetadataStore, e.g. because it expired
+	ErrMetadataNotFound = errors.New("Private metadata not found")
+)
+
+// A store for values too large for a view's private_metadata
+type MetadataStore interface {
+	// Saves data with a key
+	Save(key string, data []byte) error
+	// Loads the data saved with a key. Returns ErrMetadataNotFound
+	// if it doesn't exist.
+	Load(key string) ([]byte, error)
+	// Deletes the data saved with a key
+	Delete(key string) error
+}
+
+// A MetadataStore that keeps data in memory for 24 hours.
+//
+// Only suitable for apps running a single instance.
+type MemoryMetadataStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]metadataEntry
+}
+
+type metadataEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// Creates an empty MemoryMetadataStore.
+func NewMemoryMetadataStore() *MemoryMetadataStore {
+	return &MemoryMetadataStore{
+		ttl:     defaultMetadataTTL,
+		entries: make(map[string]metadataEntry),
+	}
+}
+
+// Saves data with a key, removing any expired data.
+func (s *MemoryMetadataStore) Save(key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = metadataEntry{data: data, expiresAt: now.Add(s.ttl)}
+	return nil
+}
+
+// Loads the data saved with a key.
+func (s *MemoryMetadataStore) Load(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, ErrMetadataNotFound
+	}
+	return entry.data, nil
+}
+
+// Deletes the data saved with a key.
+func (s *MemoryMetadataStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// Encodes a value as JSON for a view's private_metadata, signed so
+// that it can be verified when decoded. Large values are compressed,
+// and values that are still too large are kept in the
+// Config.MetadataStore.
+func (req *baseRequest) EncodeMetadata(v any) (string, error) {
+	return req.app.encodeMetadata(v)
+}
+
+// Decodes the view's private_metadata, created with EncodeMetadata,
+// into v.
+//
+// Returns ErrInvalidMetadata if it was not created by EncodeMetadata
+// or has been modified.
+func (req *ViewSubmissionRequest) DecodeMetadata(v any) error {
+	return req.app.decodeMetadata(req.Payload.View.PrivateMetadata, v)
+}
+
+// Decodes the closed view's private_metadata, created with
+// EncodeMetadata, into v.
+//
+// Returns ErrInvalidMetadata if it was not created by EncodeMetadata
+// or has been modified.
+func (req *ViewClosedRequest) DecodeMetadata(v any) error {
+	return req.app.decodeMetadata(req.Payload.View.PrivateMetadata, v)
+}
+
+// Decodes the private_metadata, created with EncodeMetadata, of the
+// view containing the action into v.
+//
+// Returns ErrInvalidMetadata if the action is not in a view, or the
+// private_metadata was not created by EncodeMetadata or has been
+// modified.
+func (req *BlockActionRequest) DecodeMetadata(v any) error {
+	if req.Payload.View == nil {
+		return ErrInvalidMetadata
+	}
+	return req.app.decodeMetadata(req.Payload.View.PrivateMetadata, v)
+}
+
+// Deletes the view's private_metadata from the Config.MetadataStore,
+// if it was kept there. Call it when the value is no longer needed,
+// e.g. in a view closed handler.
+func (req *ViewClosedRequest) DeleteMetadata() error {
+	return req.app.deleteMetadata(req.Payload.View.PrivateMetadata)
+}
+
+func (app *Application) encodeMetadata(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	mode, payload := metadataJSON, base64.RawURLEncoding.EncodeToString(data)
+	if len(data) >= compressMetadataLength {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		writer.Write(data)
+		if err = writer.Close(); err != nil {
+			return "", err
+		}
+		mode, payload = metadataCompressed, base64.RawURLEncoding.EncodeToString(buf.Bytes())
+	}
+
+	metadata := app.signMetadata(mode, payload)
+	if len(metadata) <= maxPrivateMetadataLength {
+		return metadata, nil
+	}
+
+	if app.metadataStore == nil {
+		return "", ErrMetadataTooLarge
+	}
+	key := make([]byte, 16)
+	if _, err = rand.Read(key); err != nil {
+		return "", err
+	}
+	payload = hex.EncodeToString(key)
+	if err = app.metadataStore.Save(payload, data); err != nil {
+		return "", err
+	}
+	return app.signMetadata(metadataStored, payload), nil
+}
+
+func (app *Application) decodeMetadata(metadata string, v any) error {
+	mode, payload, err := app.verifyMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch mode {
+	case metadataStored:
+		data, err = app.metadataStore.Load(payload)
+		if err != nil {
+			return err
+		}
+	case metadataJSON, metadataCompressed:
+		data, err = base64.RawURLEncoding.DecodeString(payload)
+		if err != nil {
+			return ErrInvalidMetadata
+		}
+		if mode == metadataCompressed {
+			reader, err := gzip.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return ErrInvalidMetadata
+			}
+			if data, err = io.ReadAll(reader); err != nil {
+				return ErrInvalidMetadata
+			}
+		}
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (app *Application) deleteMetadata(metadata string) error {
+	mode, payload, err := app.verifyMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	if mode != metadataStored {
+		return nil
+	}
+	return app.metadataStore.Delete(payload)
+}
+
+// Whether metadata is kept in the Config.MetadataStore.
+func isStoredMetadata(metadata string) bool {
+	return strings.HasPrefix(metadata, metadataStored+".")
+}
+
+// Signs metadata as "mode.payload.signature".
+func (app *Application) signMetadata(mode string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(app.metadataSecret))
+	mac.Write([]byte(mode + "." + payload))
+	return mode + "." + payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verifies signed metadata, returning its mode and payload.
+func (app *Application) verifyMetadata(metadata string) (string, string, error) {
+	parts := strings.Split(metadata, ".")
+	if len(parts) != 3 {
+		return "", "", ErrInvalidMetadata
+	}
+	mode, payload := parts[0], parts[1]
+	if mode != metadataJSON && mode != metadataCompressed && mode != metadataStored {
+		return "", "", ErrInvalidMetadata
+	}
+	if mode == metadataStored && app.metadataStore == nil {
+		return "", "", ErrInvalidMetadata
+	}
+	if !hmac.Equal([]byte(app.signMetadata(mode, payload)), []byte(metadata)) {
+		return "", "", ErrInvalidMetadata
+	}
+	return mode, payload, nil
+}
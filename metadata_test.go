@@ -0,0 +1,225 @@
+package slap_test
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+type wizardState struct {
+	Step  int      `json:"step"`
+	Notes []string `json:"notes"`
+}
+
+type metadataTester struct {
+	app     *slap.Application
+	tester  *slaptest.Tester
+	values  chan any
+	encoded chan metadataResult
+	decoded chan metadataResult
+}
+
+type metadataResult struct {
+	metadata string
+	state    wizardState
+	err      error
+}
+
+func newMetadataTester(store slap.MetadataStore) *metadataTester {
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		MetadataStore: store,
+	})
+	m := &metadataTester{
+		app:     app,
+		tester:  slaptest.New(router, "signing-secret"),
+		values:  make(chan any, 1),
+		encoded: make(chan metadataResult, 1),
+		decoded: make(chan metadataResult, 1),
+	}
+	app.RegisterCommand("/encode", func(req *slap.CommandRequest) error {
+		req.Ack()
+		metadata, err := req.EncodeMetadata(<-m.values)
+		m.encoded <- metadataResult{metadata: metadata, err: err}
+		return nil
+	})
+	app.RegisterViewSubmission("wizard", func(req *slap.ViewSubmissionRequest) error {
+		req.Ack()
+		var state wizardState
+		err := req.DecodeMetadata(&state)
+		m.decoded <- metadataResult{state: state, err: err}
+		return nil
+	})
+	return m
+}
+
+// Encodes a value as private_metadata from a command handler.
+func (m *metadataTester) encode(v any) (string, error) {
+	m.values <- v
+	m.tester.Command(slaptest.Command{Command: "/encode"})
+	r := <-m.encoded
+	return r.metadata, r.err
+}
+
+// Decodes private_metadata from a view submission handler.
+func (m *metadataTester) decode(metadata string) (wizardState, error) {
+	m.tester.ViewSubmission(slaptest.ViewSubmission{
+		View: slack.View{CallbackID: "wizard", PrivateMetadata: metadata},
+	})
+	r := <-m.decoded
+	return r.state, r.err
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	m := newMetadataTester(nil)
+	metadata, err := m.encode(wizardState{Step: 2, Notes: []string{"a", "b"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	state, err := m.decode(metadata)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	stepGot, stepWant := state.Step, 2
+	if stepGot != stepWant {
+		t.Errorf("Unexpected step, got: %v, want: %v", stepGot, stepWant)
+	}
+
+	notesGot, notesWant := strings.Join(state.Notes, ","), "a,b"
+	if notesGot != notesWant {
+		t.Errorf("Unexpected notes, got: %v, want: %v", notesGot, notesWant)
+	}
+}
+
+func TestMetadataCompressed(t *testing.T) {
+	t.Parallel()
+
+	notes := make([]string, 200)
+	for i := range notes {
+		notes[i] = "the same note"
+	}
+
+	m := newMetadataTester(nil)
+	metadata, err := m.encode(wizardState{Notes: notes})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	if len(metadata) > 3000 {
+		t.Errorf("Expected compressed metadata, got %v characters", len(metadata))
+	}
+
+	state, err := m.decode(metadata)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	countGot, countWant := len(state.Notes), 200
+	if countGot != countWant {
+		t.Errorf("Unexpected number of notes, got: %v, want: %v", countGot, countWant)
+	}
+}
+
+func TestMetadataTampered(t *testing.T) {
+	t.Parallel()
+
+	m := newMetadataTester(nil)
+	metadata, err := m.encode(wizardState{Step: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	parts := strings.Split(metadata, ".")
+	parts[1] = "eyJzdGVwIjo5fQ" // {"step":9}
+	_, err = m.decode(strings.Join(parts, "."))
+	if !errors.Is(err, slap.ErrInvalidMetadata) {
+		t.Errorf("Unexpected error, got: %v, want: %v", err, slap.ErrInvalidMetadata)
+	}
+}
+
+func TestMetadataUnsigned(t *testing.T) {
+	t.Parallel()
+
+	m := newMetadataTester(nil)
+	_, err := m.decode(`{"step":9}`)
+	if !errors.Is(err, slap.ErrInvalidMetadata) {
+		t.Errorf("Unexpected error, got: %v, want: %v", err, slap.ErrInvalidMetadata)
+	}
+}
+
+// Random notes that can't be compressed below Slack's limit
+func largeNotes() []string {
+	random := rand.New(rand.NewSource(1))
+	notes := make([]string, 400)
+	for i := range notes {
+		notes[i] = strconv.FormatUint(random.Uint64(), 36)
+	}
+	return notes
+}
+
+func TestMetadataTooLarge(t *testing.T) {
+	t.Parallel()
+
+	m := newMetadataTester(nil)
+	_, err := m.encode(wizardState{Notes: largeNotes()})
+	if !errors.Is(err, slap.ErrMetadataTooLarge) {
+		t.Errorf("Unexpected error, got: %v, want: %v", err, slap.ErrMetadataTooLarge)
+	}
+}
+
+func TestMetadataStore(t *testing.T) {
+	t.Parallel()
+
+	store := slap.NewMemoryMetadataStore()
+	m := newMetadataTester(store)
+	metadata, err := m.encode(wizardState{Notes: largeNotes()})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+	if len(metadata) > 3000 {
+		t.Errorf("Expected a stored key, got %v characters", len(metadata))
+	}
+
+	state, err := m.decode(metadata)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	countGot, countWant := len(state.Notes), 400
+	if countGot != countWant {
+		t.Errorf("Unexpected number of notes, got: %v, want: %v", countGot, countWant)
+	}
+
+	deleted := make(chan error, 1)
+	m.app.RegisterViewClosed("wizard", func(req *slap.ViewClosedRequest) error {
+		req.Ack()
+		deleted <- req.DeleteMetadata()
+		return nil
+	})
+	m.tester.ViewClosed(slaptest.ViewClosed{
+		View: slack.View{CallbackID: "wizard", PrivateMetadata: metadata},
+	})
+	if err := <-deleted; err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	_, err = m.decode(metadata)
+	if !errors.Is(err, slap.ErrMetadataNotFound) {
+		t.Errorf("Unexpected error, got: %v, want: %v", err, slap.ErrMetadataNotFound)
+	}
+}
@@ -0,0 +1,304 @@
+package slap
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// Returned when the OAuth state parameter is missing, tampered with,
+// expired, already used or from another browser.
+var ErrInvalidOAuthState = errors.New("Invalid OAuth state")
+
+// How long a user has to complete the OAuth flow after starting it.
+const oauthStateTTL = 10 * time.Minute
+
+// The cookie tying the OAuth state to the browser that started the flow.
+const oauthStateCookie = "slap_oauth_state"
+
+// Configuration for the "Add to Slack" OAuth v2 installation flow.
+//
+// Slap will register the following GET routes:
+// "GET /install", which redirects the user to Slack, and
+// "GET /oauth_redirect", which completes the installation.
+type OAuthConfig struct {
+	// Required. Your Slack App's client ID.
+	ClientID string
+	// Required. Your Slack App's client secret.
+	ClientSecret string
+	// The bot scopes to request, e.g. "commands", "chat:write".
+	Scopes []string
+	// The user scopes to request.
+	UserScopes []string
+	// Optional. The redirect URL configured in your Slack App's settings.
+	RedirectURL string
+	// Optional. The secret used to sign the state parameter.
+	//
+	// Defaults to a key derived from the signing secret.
+	StateSecret string
+	// Optional. Defaults to: "https://slack.com/oauth/v2/authorize".
+	AuthorizeURL string
+	// Optional. The Slack Web API base URL used to exchange codes.
+	//
+	// Defaults to Config.APIURL.
+	APIURL string
+	// Optional. Called after an installation has been saved.
+	//
+	// Defaults to a plain text success message.
+	OnSuccess func(w http.ResponseWriter, r *http.Request, installation Installation)
+	// Optional. Called when the installation fails.
+	//
+	// Defaults to a plain text error with status 400.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func (c *OAuthConfig) setDefaults(signingSecret string, apiURL string) {
+	if c.ClientID == "" || c.ClientSecret == "" {
+		panic("Missing OAuth client ID or client secret")
+	}
+	if c.StateSecret == "" {
+		c.StateSecret = deriveSecret(signingSecret, "slap-oauth-state")
+	}
+	if c.AuthorizeURL == "" {
+		c.AuthorizeURL = "https://slack.com/oauth/v2/authorize"
+	}
+	if c.APIURL == "" {
+		c.APIURL = apiURL
+	}
+	if !strings.HasSuffix(c.APIURL, "/") {
+		c.APIURL += "/"
+	}
+	if c.OnSuccess == nil {
+		c.OnSuccess = func(w http.ResponseWriter, _ *http.Request, _ Installation) {
+			w.Header().Set("content-type", "text/plain")
+			w.Write([]byte("The Slack App has been installed successfully"))
+		}
+	}
+	if c.OnError == nil {
+		c.OnError = func(w http.ResponseWriter, _ *http.Request, _ error) {
+			http.Error(w, "The Slack App could not be installed", http.StatusBadRequest)
+		}
+	}
+}
+
+func (app *Application) handleInstall(w http.ResponseWriter, r *http.Request) {
+	state, err := newOAuthState(app.oauth.StateSecret, time.Now())
+	if err != nil {
+		app.logger.Error("Could not create OAuth state", "error", err.Error())
+		http.Error(w, "An error occurred", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   int(oauthStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || strings.HasPrefix(app.oauth.RedirectURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := url.Values{}
+	query.Set("client_id", app.oauth.ClientID)
+	query.Set("scope", strings.Join(app.oauth.Scopes, ","))
+	query.Set("state", state)
+	if len(app.oauth.UserScopes) > 0 {
+		query.Set("user_scope", strings.Join(app.oauth.UserScopes, ","))
+	}
+	if app.oauth.RedirectURL != "" {
+		query.Set("redirect_uri", app.oauth.RedirectURL)
+	}
+
+	http.Redirect(w, r, app.oauth.AuthorizeURL+"?"+query.Encode(), http.StatusFound)
+}
+
+func (app *Application) handleOAuthRedirect(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	// The state can only be used once, so the cookie is always cleared
+	http.SetCookie(w, &http.Cookie{Name: oauthStateCookie, Path: "/", MaxAge: -1})
+
+	if slackErr := query.Get("error"); slackErr != "" {
+		app.logger.Warn("Slack App installation was not authorized", "error", slackErr)
+		app.oauth.OnError(w, r, fmt.Errorf("Installation was not authorized: %v", slackErr))
+		return
+	}
+
+	if err := app.verifyOAuthState(r, query.Get("state"), time.Now()); err != nil {
+		app.logger.Warn("Invalid OAuth state", "error", err.Error())
+		app.oauth.OnError(w, r, err)
+		return
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		app.oauth.OnError(w, r, errors.New("Missing OAuth code"))
+		return
+	}
+
+	values := url.Values{}
+	values.Set("code", code)
+	if app.oauth.RedirectURL != "" {
+		values.Set("redirect_uri", app.oauth.RedirectURL)
+	}
+
+	res, err := app.oauthV2Access(values)
+	if err != nil {
+		app.logger.Error("Could not exchange OAuth code", "error", err.Error())
+		app.oauth.OnError(w, r, err)
+		return
+	}
+
+	installation := Installation{
+		AppID:               res.AppID,
+		EnterpriseID:        res.Enterprise.ID,
+		EnterpriseName:      res.Enterprise.Name,
+		TeamID:              res.Team.ID,
+		TeamName:            res.Team.Name,
+		IsEnterpriseInstall: res.IsEnterpriseInstall,
+		BotUserID:           res.BotUserID,
+		BotToken:            res.AccessToken,
+		BotScopes:           res.Scope,
+		UserID:              res.AuthedUser.ID,
+		UserToken:           res.AuthedUser.AccessToken,
+		UserScopes:          res.AuthedUser.Scope,
+		UserRefreshToken:    res.AuthedUser.RefreshToken,
+		BotRefreshToken:     res.RefreshToken,
+		InstalledAt:         time.Now(),
+	}
+	if res.ExpiresIn > 0 {
+		installation.BotTokenExpiresAt = installation.InstalledAt.Add(time.Duration(res.ExpiresIn) * time.Second)
+	}
+	if res.AuthedUser.ExpiresIn > 0 {
+		installation.UserTokenExpiresAt = installation.InstalledAt.Add(time.Duration(res.AuthedUser.ExpiresIn) * time.Second)
+	}
+
+	if err = app.installations.Save(installation); err != nil {
+		app.logger.Error("Could not save installation", "teamID", installation.TeamID, "error", err.Error())
+		app.oauth.OnError(w, r, err)
+		return
+	}
+
+	app.logger.Info("Slack App installed", "teamID", installation.TeamID, "enterpriseID", installation.EnterpriseID)
+	app.oauth.OnSuccess(w, r, installation)
+}
+
+// Calls Slack's oauth.v2.access method with the app's client credentials.
+func (app *Application) oauthV2Access(values url.Values) (*slack.OAuthV2Response, error) {
+	values.Set("client_id", app.oauth.ClientID)
+	values.Set("client_secret", app.oauth.ClientSecret)
+
+	res, err := app.httpClient.PostForm(app.oauth.APIURL+"oauth.v2.access", values)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oauth.v2.access returned status %v", res.StatusCode)
+	}
+
+	var out slack.OAuthV2Response
+	if err = json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	if err = out.Err(); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Creates a state parameter of the form "<timestamp>.<nonce>.<signature>".
+func newOAuthState(secret string, now time.Time) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	payload := strconv.FormatInt(now.Unix(), 10) + "." + base64.RawURLEncoding.EncodeToString(nonce)
+	return payload + "." + signOAuthState(secret, payload), nil
+}
+
+// Verifies that the state was created by handleInstall for the same
+// browser, and has not been used before.
+func (app *Application) verifyOAuthState(r *http.Request, state string, now time.Time) error {
+	if err := verifyOAuthState(app.oauth.StateSecret, state, now); err != nil {
+		return err
+	}
+	cookie, err := r.Cookie(oauthStateCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) == 0 {
+		return ErrInvalidOAuthState
+	}
+	if !app.oauthStates.use(state, now) {
+		return ErrInvalidOAuthState
+	}
+	return nil
+}
+
+func verifyOAuthState(secret string, state string, now time.Time) error {
+	i := strings.LastIndex(state, ".")
+	if i < 0 {
+		return ErrInvalidOAuthState
+	}
+	payload, signature := state[:i], state[i+1:]
+
+	expected := signOAuthState(secret, payload)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) == 0 {
+		return ErrInvalidOAuthState
+	}
+
+	timestamp, _, _ := strings.Cut(payload, ".")
+	issued, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidOAuthState
+	}
+	if now.Sub(time.Unix(issued, 0)) > oauthStateTTL {
+		return ErrInvalidOAuthState
+	}
+	return nil
+}
+
+func signOAuthState(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// The OAuth states that have been used, kept until they expire.
+type usedOAuthStates struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+func newUsedOAuthStates() *usedOAuthStates {
+	return &usedOAuthStates{entries: make(map[string]time.Time)}
+}
+
+// Marks a state as used. Returns false if it was already used.
+func (s *usedOAuthStates) use(state string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for used, expiresAt := range s.entries {
+		if now.After(expiresAt) {
+			delete(s.entries, used)
+		}
+	}
+	if _, ok := s.entries[state]; ok {
+		return false
+	}
+	s.entries[state] = now.Add(oauthStateTTL)
+	return true
+}
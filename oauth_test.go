@@ -0,0 +1,207 @@
+package slap_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+)
+
+func createFakeOAuthServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/oauth.v2.access" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Could not parse form: %v", err.Error())
+		}
+		if r.PostForm.Get("client_id") != "client-id" || r.PostForm.Get("client_secret") != "client-secret" {
+			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "invalid_client_id"})
+			return
+		}
+		if r.PostForm.Get("code") != "good-code" {
+			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "invalid_code"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"ok":           true,
+			"access_token": "xoxb-test",
+			"token_type":   "bot",
+			"scope":        "commands,chat:write",
+			"bot_user_id":  "U0BOT",
+			"app_id":       "A0123456",
+			"team":         map[string]string{"id": "T0123456", "name": "Slap"},
+			"authed_user":  map[string]string{"id": "U0123456"},
+		})
+	}))
+}
+
+func createOAuthTestApp(apiURL string) (*slap.Application, *http.ServeMux, *slap.MemoryInstallationStore) {
+	router := http.NewServeMux()
+	store := slap.NewMemoryInstallationStore()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		Installations: store,
+		OAuth: &slap.OAuthConfig{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			Scopes:       []string{"commands", "chat:write"},
+			APIURL:       apiURL + "/api/",
+		},
+	})
+	return app, router, store
+}
+
+func startInstall(t *testing.T, router *http.ServeMux) (string, *http.Cookie) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/install", nil)
+	router.ServeHTTP(w, r)
+	res := w.Result()
+
+	statusGot, statusWant := res.StatusCode, http.StatusFound
+	if statusGot != statusWant {
+		t.Fatalf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	location, err := url.Parse(res.Header.Get("location"))
+	if err != nil {
+		t.Fatalf("Could not parse location: %v", err.Error())
+	}
+
+	scopeGot, scopeWant := location.Query().Get("scope"), "commands,chat:write"
+	if scopeGot != scopeWant {
+		t.Errorf("Unexpected scope, got: %v, want: %v", scopeGot, scopeWant)
+	}
+
+	var cookie *http.Cookie
+	for _, c := range res.Cookies() {
+		if c.Name == "slap_oauth_state" {
+			cookie = c
+		}
+	}
+	if cookie == nil {
+		t.Fatalf("Expected a state cookie")
+	}
+
+	return location.Query().Get("state"), cookie
+}
+
+func finishInstall(router *http.ServeMux, code string, state string, cookie *http.Cookie) *http.Response {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/oauth_redirect?code="+code+"&state="+url.QueryEscape(state), nil)
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+	router.ServeHTTP(w, r)
+	return w.Result()
+}
+
+func TestOAuthInstall(t *testing.T) {
+	t.Parallel()
+
+	server := createFakeOAuthServer(t)
+	defer server.Close()
+
+	_, router, store := createOAuthTestApp(server.URL)
+	state, cookie := startInstall(t, router)
+	res := finishInstall(router, "good-code", state, cookie)
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	installation, err := store.Find(slap.InstallationQuery{TeamID: "T0123456"})
+	if err != nil {
+		t.Fatalf("Could not find installation: %v", err.Error())
+	}
+
+	tokenGot, tokenWant := installation.BotToken, "xoxb-test"
+	if tokenGot != tokenWant {
+		t.Errorf("Unexpected bot token, got: %v, want: %v", tokenGot, tokenWant)
+	}
+}
+
+func TestOAuthInvalidState(t *testing.T) {
+	t.Parallel()
+
+	server := createFakeOAuthServer(t)
+	defer server.Close()
+
+	_, router, store := createOAuthTestApp(server.URL)
+	state, cookie := startInstall(t, router)
+	res := finishInstall(router, "good-code", state+"0", cookie)
+
+	statusGot, statusWant := res.StatusCode, http.StatusBadRequest
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	if _, err := store.Find(slap.InstallationQuery{TeamID: "T0123456"}); err != slap.ErrInstallationNotFound {
+		t.Errorf("Unexpected installation lookup error, got: %v, want: %v", err, slap.ErrInstallationNotFound)
+	}
+}
+
+func TestOAuthBadCode(t *testing.T) {
+	t.Parallel()
+
+	server := createFakeOAuthServer(t)
+	defer server.Close()
+
+	_, router, _ := createOAuthTestApp(server.URL)
+	state, cookie := startInstall(t, router)
+	res := finishInstall(router, "bad-code", state, cookie)
+
+	statusGot, statusWant := res.StatusCode, http.StatusBadRequest
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+}
+
+func TestOAuthStateFromAnotherBrowser(t *testing.T) {
+	t.Parallel()
+
+	server := createFakeOAuthServer(t)
+	defer server.Close()
+
+	_, router, store := createOAuthTestApp(server.URL)
+	state, _ := startInstall(t, router)
+	_, otherCookie := startInstall(t, router)
+
+	for _, cookie := range []*http.Cookie{nil, otherCookie} {
+		res := finishInstall(router, "good-code", state, cookie)
+		statusGot, statusWant := res.StatusCode, http.StatusBadRequest
+		if statusGot != statusWant {
+			t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+		}
+	}
+
+	if _, err := store.Find(slap.InstallationQuery{TeamID: "T0123456"}); err != slap.ErrInstallationNotFound {
+		t.Errorf("Unexpected installation lookup error, got: %v, want: %v", err, slap.ErrInstallationNotFound)
+	}
+}
+
+func TestOAuthStateReused(t *testing.T) {
+	t.Parallel()
+
+	server := createFakeOAuthServer(t)
+	defer server.Close()
+
+	_, router, _ := createOAuthTestApp(server.URL)
+	state, cookie := startInstall(t, router)
+
+	statusGot, statusWant := finishInstall(router, "good-code", state, cookie).StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Fatalf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	statusGot, statusWant = finishInstall(router, "good-code", state, cookie).StatusCode, http.StatusBadRequest
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code for a reused state, got: %v, want: %v", statusGot, statusWant)
+	}
+}
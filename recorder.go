@@ -0,0 +1,172 @@
+package slap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// The value secrets are replaced with in recordings.
+const Redacted = "REDACTED"
+
+// Payload keys whose values are redacted in recordings.
+var redactedKeys = map[string]bool{
+	"token":            true,
+	"bot_access_token": true,
+	"access_token":     true,
+	"refresh_token":    true,
+	"response_url":     true,
+}
+
+// A request from Slack and Slap's acknowledgement of it.
+type Record struct {
+	// When the request was received.
+	Time time.Time `json:"time"`
+	// The request path, e.g. "/commands".
+	Path string `json:"path"`
+	// The request's content type.
+	ContentType string `json:"content_type"`
+	// The request body with secrets redacted.
+	Body string `json:"body"`
+	// The response status code.
+	Status int `json:"status"`
+	// The response body.
+	Response string `json:"response"`
+}
+
+// Records verified requests from Slack and Slap's responses
+// as JSON lines, for replaying with the replay package.
+//
+// Tokens and response URLs are redacted from recorded requests.
+type Recorder struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// Creates a Recorder that writes JSON lines to w, such as an *os.File.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{encoder: json.NewEncoder(w)}
+}
+
+func (rec *Recorder) write(record Record) error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.encoder.Encode(record)
+}
+
+// Captures the response written by a handler.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Records requests passed to a handler if a Recorder is configured.
+// Must be wrapped by validateSignature so only verified requests are recorded.
+func (app *Application) record(handler http.HandlerFunc) http.HandlerFunc {
+	if app.recorder == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			app.logger.Error("Could not read request body", "error", err.Error())
+			http.Error(w, "Internal Error", http.StatusInternalServerError)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewBuffer(body))
+
+		recorder := &recordingWriter{ResponseWriter: w}
+		received := time.Now()
+		handler(recorder, r)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		err = app.recorder.write(Record{
+			Time:        received,
+			Path:        r.URL.Path,
+			ContentType: r.Header.Get("content-type"),
+			Body:        string(redactBody(body)),
+			Status:      status,
+			Response:    recorder.body.String(),
+		})
+		if err != nil {
+			app.logger.Error("Could not write record", "error", err.Error())
+		}
+	}
+}
+
+// Redacts secrets from a form or JSON request body.
+func redactBody(body []byte) []byte {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return redactJSON(body)
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return body
+	}
+	for key, values := range form {
+		if redactedKeys[key] {
+			form.Set(key, Redacted)
+		} else if key == "payload" && len(values) > 0 {
+			form.Set(key, string(redactJSON([]byte(values[0]))))
+		}
+	}
+	return []byte(form.Encode())
+}
+
+func redactJSON(blob []byte) []byte {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(blob))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return blob
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return blob
+	}
+	return redacted
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			if _, isString := inner.(string); isString && redactedKeys[key] {
+				v[key] = Redacted
+			} else {
+				v[key] = redactValue(inner)
+			}
+		}
+	case []any:
+		for i, inner := range v {
+			v[i] = redactValue(inner)
+		}
+	}
+	return value
+}
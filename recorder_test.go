@@ -0,0 +1,100 @@
+package slap_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+)
+
+func TestRecorderRedactsCommand(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		Recorder: slap.NewRecorder(&out),
+	})
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.AckWithAction(slap.CommandResponseAction{
+			ResponseType: slap.RespondInChannel,
+			Text:         "Howdy!",
+		})
+		return nil
+	})
+
+	body, err := url.ParseQuery(string(testCommandBody()))
+	if err != nil {
+		t.Fatalf("Could not parse command body: %v", err.Error())
+	}
+	body.Set("token", "verification-token")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/commands", bytes.NewReader([]byte(body.Encode())))
+	r.Header.Add("content-type", "application/x-www-form-urlencoded")
+	addSignatureHeaders(r)
+	router.ServeHTTP(w, r)
+
+	var record slap.Record
+	if err = json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("Could not decode record: %v", err.Error())
+	}
+
+	recorded, err := url.ParseQuery(record.Body)
+	if err != nil {
+		t.Fatalf("Could not parse recorded body: %v", err.Error())
+	}
+
+	tokenGot, tokenWant := recorded.Get("token"), slap.Redacted
+	if tokenGot != tokenWant {
+		t.Errorf("Unexpected token, got: %v, want: %v", tokenGot, tokenWant)
+	}
+
+	urlGot, urlWant := recorded.Get("response_url"), slap.Redacted
+	if urlGot != urlWant {
+		t.Errorf("Unexpected response URL, got: %v, want: %v", urlGot, urlWant)
+	}
+
+	textGot, textWant := recorded.Get("text"), "me"
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %v, want: %v", textGot, textWant)
+	}
+
+	responseGot, responseWant := record.Response, `{"response_type":"in_channel","text":"Howdy!"}`
+	if responseGot != responseWant {
+		t.Errorf("Unexpected response, got: %v, want: %v", responseGot, responseWant)
+	}
+}
+
+func TestRecorderSkipsUnverified(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	router := http.NewServeMux()
+	slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		Recorder: slap.NewRecorder(&out),
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/commands", bytes.NewReader(testCommandBody()))
+	router.ServeHTTP(w, r)
+
+	if strings.TrimSpace(out.String()) != "" {
+		t.Errorf("Unexpected record for an unverified request: %v", out.String())
+	}
+}
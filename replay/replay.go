@@ -0,0 +1,156 @@
+// This package replays requests recorded by a slap.Recorder
+// against a local Slap Application and compares its responses
+// with the recorded ones.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+)
+
+// Sends a re-signed request to an application.
+type Target interface {
+	Send(path string, contentType string, body []byte) (status int, response []byte, err error)
+}
+
+// The outcome of replaying a record.
+type Result struct {
+	Record slap.Record
+	// The status code of the replayed request.
+	Status int
+	// The response body of the replayed request.
+	Response string
+	// A description of how the response differs from the
+	// recorded response, or empty if they match.
+	Diff string
+}
+
+// Whether the replayed response matches the recorded response.
+func (r Result) Matches() bool {
+	return r.Diff == ""
+}
+
+// Reads records from JSON lines written by a slap.Recorder.
+func ReadRecords(r io.Reader) ([]slap.Record, error) {
+	var records []slap.Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record slap.Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("Invalid record on line %v: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// Replays records against a target in order.
+func Run(target Target, records []slap.Record) ([]Result, error) {
+	results := make([]Result, 0, len(records))
+	for _, record := range records {
+		status, response, err := target.Send(record.Path, record.ContentType, []byte(record.Body))
+		if err != nil {
+			return results, fmt.Errorf("Could not replay request to %v: %w", record.Path, err)
+		}
+		results = append(results, Result{
+			Record:   record,
+			Status:   status,
+			Response: string(response),
+			Diff:     diff(record, status, response),
+		})
+	}
+	return results, nil
+}
+
+// A Target that sends requests to an application's router in-process.
+func Handler(router http.Handler, signingSecret string) Target {
+	return handlerTarget{tester: slaptest.New(router, signingSecret)}
+}
+
+type handlerTarget struct {
+	tester *slaptest.Tester
+}
+
+func (t handlerTarget) Send(path string, contentType string, body []byte) (int, []byte, error) {
+	res := t.tester.Do(path, contentType, body)
+	return res.StatusCode, res.Body, nil
+}
+
+// A Target that sends requests over HTTP to a running application,
+// e.g. "http://localhost:4000".
+func URL(baseURL string, signingSecret string, client *http.Client) Target {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return urlTarget{baseURL: strings.TrimSuffix(baseURL, "/"), signingSecret: signingSecret, client: client}
+}
+
+type urlTarget struct {
+	baseURL       string
+	signingSecret string
+	client        *http.Client
+}
+
+func (t urlTarget) Send(path string, contentType string, body []byte) (int, []byte, error) {
+	r, err := http.NewRequest(http.MethodPost, t.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	r.Header.Set("content-type", contentType)
+	if err = slaptest.Sign(r, t.signingSecret); err != nil {
+		return 0, nil, err
+	}
+
+	res, err := t.client.Do(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+
+	response, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return res.StatusCode, response, nil
+}
+
+// Describes the differences between a recorded and replayed response.
+// JSON responses are compared ignoring formatting and key order.
+func diff(record slap.Record, status int, response []byte) string {
+	var lines []string
+	if status != record.Status {
+		lines = append(lines, fmt.Sprintf("status: recorded %v, replayed %v", record.Status, status))
+	}
+
+	recorded, replayed := normalize([]byte(record.Response)), normalize(response)
+	if recorded != replayed {
+		lines = append(lines, "response:", "- "+recorded, "+ "+replayed)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func normalize(body []byte) string {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(canonical)
+}
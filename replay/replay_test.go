@@ -0,0 +1,85 @@
+package replay_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/replay"
+	"github.com/jacob-ian/slap/slaptest"
+)
+
+func createTestApp(recorder *slap.Recorder, reply string) *http.ServeMux {
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		Recorder: recorder,
+	})
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.AckWithAction(slap.CommandResponseAction{
+			ResponseType: slap.RespondEphemeral,
+			Text:         reply,
+		})
+		return nil
+	})
+	return router
+}
+
+func record(t *testing.T) []slap.Record {
+	var out bytes.Buffer
+	router := createTestApp(slap.NewRecorder(&out), "Howdy!")
+	tester := slaptest.New(router, "signing-secret")
+	tester.Command(slaptest.Command{Command: "/help", Text: "me"})
+	tester.Command(slaptest.Command{Command: "/unknown"})
+
+	records, err := replay.ReadRecords(&out)
+	if err != nil {
+		t.Fatalf("Could not read records: %v", err.Error())
+	}
+	if len(records) != 2 {
+		t.Fatalf("Unexpected number of records, got: %v, want: %v", len(records), 2)
+	}
+	return records
+}
+
+func TestReplayMatches(t *testing.T) {
+	t.Parallel()
+
+	records := record(t)
+	router := createTestApp(nil, "Howdy!")
+
+	results, err := replay.Run(replay.Handler(router, "signing-secret"), records)
+	if err != nil {
+		t.Fatalf("Could not replay: %v", err.Error())
+	}
+
+	for _, result := range results {
+		if !result.Matches() {
+			t.Errorf("Unexpected diff for %v:\n%v", result.Record.Path, result.Diff)
+		}
+	}
+}
+
+func TestReplayDiff(t *testing.T) {
+	t.Parallel()
+
+	records := record(t)
+	router := createTestApp(nil, "Hello!")
+
+	results, err := replay.Run(replay.Handler(router, "signing-secret"), records)
+	if err != nil {
+		t.Fatalf("Could not replay: %v", err.Error())
+	}
+
+	if results[0].Matches() {
+		t.Errorf("Expected a diff for a changed response")
+	}
+	if !results[1].Matches() {
+		t.Errorf("Unexpected diff for an unchanged response:\n%v", results[1].Diff)
+	}
+}
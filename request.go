@@ -3,19 +3,58 @@ package slap
 import (
 	"log/slog"
 	"net/http"
+	"sync"
 
 	"github.com/slack-go/slack"
 )
 
 type baseRequest struct {
-	// A Slack API client
-	Client *slack.Client
 	// The logger as defined in Config
 	Logger     *slog.Logger
 	ackCalled  bool
 	ackChannel chan []byte
 	errChannel chan error
 	writer     http.ResponseWriter
+	app        *Application
+	authorize  AuthorizeContext
+	botOnce    sync.Once
+	botClient  *slack.Client
+	userOnce   sync.Once
+	userClient *slack.Client
+	userErr    error
+	localeOnce sync.Once
+	locale     string
+	// Nil when the request has no response URL
+	responseURL *responseURL
+	context     RequestContext
+}
+
+// Returns a Slack API client authorized with the bot token of the
+// request's workspace, resolving the token on first use.
+//
+// If the bot token cannot be resolved, every API call made with
+// the client returns the resolution error.
+func (req *baseRequest) Client() *slack.Client {
+	req.botOnce.Do(func() {
+		req.botClient = req.app.botClient(req.authorize)
+	})
+	return req.botClient
+}
+
+// Returns a Slack API client authorized as the user who triggered
+// the request, resolving their token on first use.
+//
+// Returns ErrUserNotAuthorized if the user has not authorized the app.
+func (req *baseRequest) UserClient() (*slack.Client, error) {
+	req.userOnce.Do(func() {
+		token, err := req.app.resolveUserToken(req.authorize)
+		if err != nil {
+			req.userErr = err
+			return
+		}
+		req.userClient = req.app.newClient(token, req.app.httpClient)
+	})
+	return req.userClient, req.userErr
 }
 
 // Acknowledge Slack's request with Status 200
@@ -0,0 +1,144 @@
+package slap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// Slack allows a response URL to be used this many times
+const responseURLMaxUses = 5
+
+// Slack allows a response URL to be used for this long
+const responseURLTTL = 30 * time.Minute
+
+var (
+	// The request has no response URL, e.g. an event
+	ErrNoResponseURL = errors.New("Request has no response URL")
+	// The response URL is older than 30 minutes
+	ErrResponseURLExpired = errors.New("Response URL has expired")
+	// The response URL has already been used five times
+	ErrResponseURLUsed = errors.New("Response URL has been used too many times")
+)
+
+// An error response from Slack when using a response URL
+type ResponseURLError struct {
+	// The HTTP status code of Slack's response
+	StatusCode int
+	// The body of Slack's response, e.g. "invalid_blocks"
+	Body string
+}
+
+func (e *ResponseURLError) Error() string {
+	return fmt.Sprintf("Response URL request failed with status %v: %v", e.StatusCode, e.Body)
+}
+
+// A message sent to a request's response URL
+type ResponseMessage struct {
+	// The type of response: "in_channel" or "ephemeral". Slack
+	// defaults to "ephemeral".
+	ResponseType CommandResponseActionType `json:"response_type,omitempty"`
+	// Text to send in the response
+	Text string `json:"text,omitempty"`
+	// Slack Block Kit Blocks to send in the response
+	Blocks []slack.Block `json:"blocks,omitempty"`
+	// Whether to replace the message the request came from
+	ReplaceOriginal bool `json:"replace_original,omitempty"`
+	// Whether to delete the message the request came from
+	DeleteOriginal bool `json:"delete_original,omitempty"`
+	// The timestamp of a message to reply to in a thread
+	ThreadTimestamp string `json:"thread_ts,omitempty"`
+}
+
+// A request's response URL and its remaining uses
+type responseURL struct {
+	url        string
+	receivedAt time.Time
+	mu         sync.Mutex
+	uses       int
+}
+
+func newResponseURL(url string) *responseURL {
+	if url == "" {
+		return nil
+	}
+	return &responseURL{url: url, receivedAt: time.Now()}
+}
+
+// Reserves one of the response URL's uses.
+func (r *responseURL) use(now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now.Sub(r.receivedAt) > responseURLTTL {
+		return ErrResponseURLExpired
+	}
+	if r.uses >= responseURLMaxUses {
+		return ErrResponseURLUsed
+	}
+	r.uses++
+	return nil
+}
+
+// Sends a message to the request's response URL, e.g. a delayed
+// response to a slash command or a replacement for the message
+// containing a block action.
+//
+// Slack allows a response URL to be used five times within 30
+// minutes of the request. Returns ErrNoResponseURL if the request
+// has no response URL, ErrResponseURLExpired or ErrResponseURLUsed
+// if its limits have been reached, or a *ResponseURLError if Slack
+// rejects the message.
+func (req *baseRequest) Respond(message ResponseMessage) error {
+	if req.responseURL == nil {
+		return ErrNoResponseURL
+	}
+	if err := req.responseURL.use(time.Now()); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	res, err := req.app.httpClient.Post(req.responseURL.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	text := strings.TrimSpace(string(resBody))
+	var result struct {
+		OK    *bool  `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err = json.Unmarshal(resBody, &result); err == nil && result.OK != nil {
+		if *result.OK && res.StatusCode == http.StatusOK {
+			return nil
+		}
+		text = result.Error
+	} else if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	switch text {
+	case "expired_url":
+		return ErrResponseURLExpired
+	case "used_url":
+		return ErrResponseURLUsed
+	}
+	return &ResponseURLError{StatusCode: res.StatusCode, Body: text}
+}
@@ -0,0 +1,147 @@
+package slap_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+type responseURLServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	messages []map[string]any
+}
+
+func newResponseURLServer(status int, body string) *responseURLServer {
+	s := &responseURLServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blob, _ := io.ReadAll(r.Body)
+		var message map[string]any
+		json.Unmarshal(blob, &message)
+		s.mu.Lock()
+		s.messages = append(s.messages, message)
+		s.mu.Unlock()
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	return s
+}
+
+func TestCommandRespond(t *testing.T) {
+	t.Parallel()
+
+	server := newResponseURLServer(http.StatusOK, "ok")
+	defer server.Close()
+
+	errs := make(chan []error, 1)
+	app, router := createTestApp()
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.Ack()
+		got := []error{}
+		for i := 0; i < 6; i++ {
+			got = append(got, req.Respond(slap.ResponseMessage{
+				ResponseType:    slap.RespondInChannel,
+				Text:            "Done!",
+				ReplaceOriginal: true,
+				ThreadTimestamp: "1234.5678",
+			}))
+		}
+		errs <- got
+		return nil
+	})
+
+	tester := slaptest.New(router, "signing-secret")
+	tester.Command(slaptest.Command{Command: "/help", ResponseURL: server.URL})
+
+	got := <-errs
+	for i, err := range got[:5] {
+		if err != nil {
+			t.Errorf("Unexpected error for response %v: %v", i, err.Error())
+		}
+	}
+	if !errors.Is(got[5], slap.ErrResponseURLUsed) {
+		t.Errorf("Unexpected error for sixth response, got: %v, want: %v", got[5], slap.ErrResponseURLUsed)
+	}
+
+	countGot, countWant := len(server.messages), 5
+	if countGot != countWant {
+		t.Fatalf("Unexpected number of responses, got: %v, want: %v", countGot, countWant)
+	}
+
+	message := server.messages[0]
+	if message["response_type"] != "in_channel" || message["replace_original"] != true || message["thread_ts"] != "1234.5678" {
+		t.Errorf("Unexpected response message: %v", message)
+	}
+	if _, ok := message["delete_original"]; ok {
+		t.Errorf("Unexpected delete_original in response message: %v", message)
+	}
+}
+
+func TestRespondErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status int
+		body   string
+		check  func(err error) bool
+	}{
+		{http.StatusNotFound, "expired_url", func(err error) bool { return errors.Is(err, slap.ErrResponseURLExpired) }},
+		{http.StatusNotFound, "used_url", func(err error) bool { return errors.Is(err, slap.ErrResponseURLUsed) }},
+		{http.StatusOK, `{"ok":false,"error":"invalid_blocks"}`, func(err error) bool {
+			var urlErr *slap.ResponseURLError
+			return errors.As(err, &urlErr) && urlErr.Body == "invalid_blocks"
+		}},
+		{http.StatusOK, `{"ok":true}`, func(err error) bool { return err == nil }},
+	}
+
+	for _, test := range tests {
+		server := newResponseURLServer(test.status, test.body)
+
+		errs := make(chan error, 1)
+		app, router := createTestApp()
+		app.RegisterBlockAction("action", func(req *slap.BlockActionRequest) error {
+			req.Ack()
+			errs <- req.Respond(slap.ResponseMessage{DeleteOriginal: true})
+			return nil
+		})
+
+		tester := slaptest.New(router, "signing-secret")
+		tester.BlockAction(slaptest.BlockAction{
+			Action:      slack.BlockAction{ActionID: "action"},
+			ResponseURL: server.URL,
+		})
+
+		err := <-errs
+		if !test.check(err) {
+			t.Errorf("Unexpected error for %v %v: %v", test.status, test.body, err)
+		}
+		server.Close()
+	}
+}
+
+func TestRespondNoResponseURL(t *testing.T) {
+	t.Parallel()
+
+	errs := make(chan error, 1)
+	app, router := createTestApp()
+	app.RegisterBlockAction("action", func(req *slap.BlockActionRequest) error {
+		req.Ack()
+		errs <- req.Respond(slap.ResponseMessage{Text: "Hi"})
+		return nil
+	})
+
+	tester := slaptest.New(router, "signing-secret")
+	tester.BlockAction(slaptest.BlockAction{Action: slack.BlockAction{ActionID: "action"}})
+
+	if err := <-errs; !errors.Is(err, slap.ErrNoResponseURL) {
+		t.Errorf("Unexpected error, got: %v, want: %v", err, slap.ErrNoResponseURL)
+	}
+}
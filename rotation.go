@@ -0,0 +1,188 @@
+package slap
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// Tokens are refreshed when they are this close to expiring.
+const tokenRefreshWindow = 2 * time.Hour
+
+// An in-flight token refresh for a workspace.
+type rotation struct {
+	query        InstallationQuery
+	done         chan struct{}
+	installation *Installation
+	err          error
+}
+
+// Reads a request's bot token from the InstallationStore,
+// refreshing it first if it is about to expire.
+func (app *Application) installationBotToken(ctx AuthorizeContext) (string, error) {
+	installation, err := app.findInstallation(ctx.installationQuery())
+	if err != nil {
+		return "", err
+	}
+	return installation.BotToken, nil
+}
+
+// Finds an installation, refreshing any expiring tokens.
+//
+// Falls back to an org-wide installation when the workspace
+// belongs to an Enterprise Grid without its own installation.
+func (app *Application) findInstallation(query InstallationQuery) (*Installation, error) {
+	installation, err := app.installations.Find(query)
+	if errors.Is(err, ErrInstallationNotFound) && !query.IsEnterpriseInstall && query.EnterpriseID != "" {
+		query.IsEnterpriseInstall = true
+		installation, err = app.installations.Find(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if app.oauth == nil || !needsRefresh(query, installation, time.Now()) {
+		return installation, nil
+	}
+	return app.rotateTokens(query)
+}
+
+// Refreshes an installation's tokens, sharing the result with any
+// concurrent callers refreshing the same installation.
+//
+// Refreshes are run one at a time for each workspace, as its
+// installation and the installing user's installation can share a
+// user refresh token, which can only be spent once. Callers with
+// another query wait, then check their installation again.
+func (app *Application) rotateTokens(query InstallationQuery) (*Installation, error) {
+	workspace := query
+	workspace.UserID = ""
+	key := workspace.key()
+	app.rotationsMu.Lock()
+	for {
+		r, ok := app.rotations[key]
+		if !ok {
+			break
+		}
+		app.rotationsMu.Unlock()
+		<-r.done
+		if r.query == query {
+			return r.installation, r.err
+		}
+		app.rotationsMu.Lock()
+	}
+	r := &rotation{query: query, done: make(chan struct{})}
+	app.rotations[key] = r
+	app.rotationsMu.Unlock()
+
+	r.installation, r.err = app.refreshInstallation(query)
+
+	app.rotationsMu.Lock()
+	delete(app.rotations, key)
+	app.rotationsMu.Unlock()
+	close(r.done)
+
+	return r.installation, r.err
+}
+
+func (app *Application) refreshInstallation(query InstallationQuery) (*Installation, error) {
+	teamID := query.TeamID
+	// Re-read the installation in case it was refreshed elsewhere
+	installation, err := app.installations.Find(query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !needsRefresh(query, installation, now) {
+		return installation, nil
+	}
+
+	if query.UserID != "" {
+		// Only the user token is refreshed for a user's installation,
+		// so keep the workspace's current bot token when saving it
+		workspace := query
+		workspace.UserID = ""
+		current, err := app.installations.Find(workspace)
+		if err == nil {
+			installation.BotToken = current.BotToken
+			installation.BotRefreshToken = current.BotRefreshToken
+			installation.BotTokenExpiresAt = current.BotTokenExpiresAt
+		}
+	} else if expiring(installation.BotRefreshToken, installation.BotTokenExpiresAt, now) {
+		token, refreshToken, expiresAt, err := app.refreshToken(installation.BotRefreshToken, now)
+		if err != nil {
+			app.logger.Error("Could not refresh bot token", "teamID", teamID, "error", err.Error())
+			return nil, err
+		}
+		installation.BotToken = token
+		installation.BotRefreshToken = refreshToken
+		installation.BotTokenExpiresAt = expiresAt
+		// Save before refreshing the user token, as Slack has already
+		// spent the old refresh token
+		if err = app.saveRefreshedInstallation(installation); err != nil {
+			return nil, err
+		}
+	}
+
+	if expiring(installation.UserRefreshToken, installation.UserTokenExpiresAt, now) {
+		token, refreshToken, expiresAt, err := app.refreshToken(installation.UserRefreshToken, now)
+		if err != nil {
+			app.logger.Error("Could not refresh user token", "teamID", teamID, "userID", query.UserID, "error", err.Error())
+			if query.UserID != "" {
+				return nil, err
+			}
+			// The bot token is still usable
+			return installation, nil
+		}
+		installation.UserToken = token
+		installation.UserRefreshToken = refreshToken
+		installation.UserTokenExpiresAt = expiresAt
+		if err = app.saveRefreshedInstallation(installation); err != nil {
+			return nil, err
+		}
+	}
+
+	return installation, nil
+}
+
+func (app *Application) saveRefreshedInstallation(installation *Installation) error {
+	if err := app.installations.Save(*installation); err != nil {
+		app.logger.Error("Could not save refreshed installation", "teamID", installation.TeamID, "error", err.Error())
+		return err
+	}
+	app.logger.Info("Refreshed installation tokens", "teamID", installation.TeamID)
+	return nil
+}
+
+// Exchanges a refresh token for a new access token and refresh token.
+func (app *Application) refreshToken(refreshToken string, now time.Time) (string, string, time.Time, error) {
+	values := url.Values{}
+	values.Set("grant_type", "refresh_token")
+	values.Set("refresh_token", refreshToken)
+
+	res, err := app.oauthV2Access(values)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	expiresAt := time.Time{}
+	if res.ExpiresIn > 0 {
+		expiresAt = now.Add(time.Duration(res.ExpiresIn) * time.Second)
+	}
+	return res.AccessToken, res.RefreshToken, expiresAt, nil
+}
+
+// Whether the tokens a query is used for are about to expire.
+//
+// User queries only need the user token.
+func needsRefresh(query InstallationQuery, installation *Installation, now time.Time) bool {
+	userExpiring := expiring(installation.UserRefreshToken, installation.UserTokenExpiresAt, now)
+	if query.UserID != "" {
+		return userExpiring
+	}
+	return userExpiring || expiring(installation.BotRefreshToken, installation.BotTokenExpiresAt, now)
+}
+
+func expiring(refreshToken string, expiresAt time.Time, now time.Time) bool {
+	return refreshToken != "" && !expiresAt.IsZero() && now.Add(tokenRefreshWindow).After(expiresAt)
+}
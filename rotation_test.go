@@ -0,0 +1,303 @@
+package slap_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jacob-ian/slap"
+)
+
+func TestTokenRotation(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth.test" {
+			w.Header().Set("content-type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"ok": true})
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Could not parse form: %v", err.Error())
+		}
+		if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "xoxe-old" {
+			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "invalid_refresh_token"})
+			return
+		}
+		refreshes.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		json.NewEncoder(w).Encode(map[string]any{
+			"ok":            true,
+			"access_token":  "xoxe.xoxb-new",
+			"refresh_token": "xoxe-new",
+			"expires_in":    43200,
+			"token_type":    "bot",
+		})
+	}))
+	defer server.Close()
+
+	store := slap.NewMemoryInstallationStore()
+	store.Save(slap.Installation{
+		TeamID:            "T0123456",
+		BotToken:          "xoxe.xoxb-old",
+		BotRefreshToken:   "xoxe-old",
+		BotTokenExpiresAt: time.Now().Add(time.Minute),
+	})
+
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		Installations: store,
+		APIURL:        server.URL,
+		OAuth: &slap.OAuthConfig{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+		},
+	})
+
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		_, err := req.Client().AuthTest()
+		if err != nil {
+			return err
+		}
+		req.Ack()
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/commands", bytes.NewReader(testCommandBody()))
+			r.Header.Add("content-type", "application/x-www-form-urlencoded")
+			addSignatureHeaders(r)
+			router.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Errorf("Unexpected status code, got: %v, want: %v", w.Code, http.StatusOK)
+			}
+		}()
+	}
+	wg.Wait()
+
+	refreshesGot, refreshesWant := refreshes.Load(), int32(1)
+	if refreshesGot != refreshesWant {
+		t.Errorf("Unexpected number of refreshes, got: %v, want: %v", refreshesGot, refreshesWant)
+	}
+
+	installation, err := store.Find(slap.InstallationQuery{TeamID: "T0123456"})
+	if err != nil {
+		t.Fatalf("Could not find installation: %v", err.Error())
+	}
+
+	tokenGot, tokenWant := installation.BotToken, "xoxe.xoxb-new"
+	if tokenGot != tokenWant {
+		t.Errorf("Unexpected bot token, got: %v, want: %v", tokenGot, tokenWant)
+	}
+
+	refreshGot, refreshWant := installation.BotRefreshToken, "xoxe-new"
+	if refreshGot != refreshWant {
+		t.Errorf("Unexpected refresh token, got: %v, want: %v", refreshGot, refreshWant)
+	}
+
+	if !installation.BotTokenExpiresAt.After(time.Now().Add(11 * time.Hour)) {
+		t.Errorf("Unexpected expiry: %v", installation.BotTokenExpiresAt)
+	}
+}
+
+func TestTokenRotationUserRefreshFails(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth.test" {
+			w.Header().Set("content-type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"ok": true})
+			return
+		}
+		r.ParseForm()
+		if r.PostForm.Get("refresh_token") != "xoxe-old" {
+			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "invalid_refresh_token"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"ok":            true,
+			"access_token":  "xoxe.xoxb-new",
+			"refresh_token": "xoxe-new",
+			"expires_in":    43200,
+			"token_type":    "bot",
+		})
+	}))
+	defer server.Close()
+
+	store := slap.NewMemoryInstallationStore()
+	store.Save(slap.Installation{
+		TeamID:             "T0123456",
+		BotToken:           "xoxe.xoxb-old",
+		BotRefreshToken:    "xoxe-old",
+		BotTokenExpiresAt:  time.Now().Add(time.Minute),
+		UserToken:          "xoxe.xoxp-old",
+		UserRefreshToken:   "xoxe-user-old",
+		UserTokenExpiresAt: time.Now().Add(time.Minute),
+	})
+
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		Installations: store,
+		APIURL:        server.URL,
+		OAuth: &slap.OAuthConfig{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+		},
+	})
+
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		_, err := req.Client().AuthTest()
+		if err != nil {
+			return err
+		}
+		req.Ack()
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/commands", bytes.NewReader(testCommandBody()))
+	r.Header.Add("content-type", "application/x-www-form-urlencoded")
+	addSignatureHeaders(r)
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected status code, got: %v, want: %v", w.Code, http.StatusOK)
+	}
+
+	installation, err := store.Find(slap.InstallationQuery{TeamID: "T0123456"})
+	if err != nil {
+		t.Fatalf("Could not find installation: %v", err.Error())
+	}
+
+	tokenGot, tokenWant := installation.BotToken, "xoxe.xoxb-new"
+	if tokenGot != tokenWant {
+		t.Errorf("Unexpected bot token, got: %v, want: %v", tokenGot, tokenWant)
+	}
+
+	refreshGot, refreshWant := installation.BotRefreshToken, "xoxe-new"
+	if refreshGot != refreshWant {
+		t.Errorf("Unexpected refresh token, got: %v, want: %v", refreshGot, refreshWant)
+	}
+
+	userRefreshGot, userRefreshWant := installation.UserRefreshToken, "xoxe-user-old"
+	if userRefreshGot != userRefreshWant {
+		t.Errorf("Unexpected user refresh token, got: %v, want: %v", userRefreshGot, userRefreshWant)
+	}
+}
+
+func TestTokenRotationSharedUserToken(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth.test" {
+			w.Header().Set("content-type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"ok": true})
+			return
+		}
+		r.ParseForm()
+		if r.PostForm.Get("refresh_token") != "xoxe-user-old" {
+			json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "invalid_refresh_token"})
+			return
+		}
+		refreshes.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		json.NewEncoder(w).Encode(map[string]any{
+			"ok":            true,
+			"access_token":  "xoxe.xoxp-new",
+			"refresh_token": "xoxe-user-new",
+			"expires_in":    43200,
+			"token_type":    "user",
+		})
+	}))
+	defer server.Close()
+
+	store := slap.NewMemoryInstallationStore()
+	store.Save(slap.Installation{
+		TeamID:             "T0123456",
+		BotToken:           "xoxb-test",
+		UserID:             "U0123456",
+		UserToken:          "xoxe.xoxp-old",
+		UserRefreshToken:   "xoxe-user-old",
+		UserTokenExpiresAt: time.Now().Add(time.Minute),
+	})
+
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		Installations: store,
+		APIURL:        server.URL,
+		OAuth: &slap.OAuthConfig{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+		},
+	})
+
+	errs := make(chan error, 10)
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.Ack()
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := req.Client().AuthTest()
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := req.UserClient()
+			errs <- err
+		}()
+		wg.Wait()
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/commands", bytes.NewReader(testCommandBody()))
+			r.Header.Add("content-type", "application/x-www-form-urlencoded")
+			addSignatureHeaders(r)
+			router.ServeHTTP(w, r)
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 10; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Unexpected error: %v", err.Error())
+		}
+	}
+
+	refreshesGot, refreshesWant := refreshes.Load(), int32(1)
+	if refreshesGot != refreshesWant {
+		t.Errorf("Unexpected number of refreshes, got: %v, want: %v", refreshesGot, refreshesWant)
+	}
+
+	installation, err := store.Find(slap.InstallationQuery{TeamID: "T0123456", UserID: "U0123456"})
+	if err != nil {
+		t.Fatalf("Could not find installation: %v", err.Error())
+	}
+	tokenGot, tokenWant := installation.UserToken, "xoxe.xoxp-new"
+	if tokenGot != tokenWant {
+		t.Errorf("Unexpected user token, got: %v, want: %v", tokenGot, tokenWant)
+	}
+}
@@ -0,0 +1,109 @@
+package slap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/slack-go/slack"
+)
+
+// The payload of a Slack message shortcut request
+type MessageShortcutPayload struct {
+	interactionPayload
+	// The callback ID of the shortcut
+	CallbackID string `json:"callback_id"`
+	// The timestamp of the message the shortcut was used on
+	MessageTimestamp string `json:"message_ts"`
+	// The message the shortcut was used on
+	Message slack.Msg `json:"message"`
+	// The channel of the message
+	Channel struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"channel"`
+	// A temporary webhook URL that used to generate message responses.
+	ResponseURL string `json:"response_url"`
+}
+
+func (p *MessageShortcutPayload) requestContext() RequestContext {
+	ctx := p.interactionPayload.requestContext()
+	ctx.ChannelID = p.Channel.ID
+	ctx.ThreadTimestamp = p.Message.ThreadTimestamp
+	ctx.ResponseURL = p.ResponseURL
+	return ctx
+}
+
+// A message shortcut request
+type MessageShortcutRequest struct {
+	baseRequest
+	Payload MessageShortcutPayload
+}
+
+// A function to handle a message shortcut request
+type MessageShortcutHandler func(req *MessageShortcutRequest) error
+
+// Registers a message shortcut handler.
+//
+// Panics if the callbackID has already been registered.
+func (app *Application) RegisterMessageShortcut(callbackID string, handler MessageShortcutHandler) {
+	_, ok := app.shortcuts[callbackID]
+	if ok {
+		panic(fmt.Sprintf("Message Shortcut Callback ID %v has already been registered", callbackID))
+	}
+	app.shortcuts[callbackID] = handler
+	app.logger.Info("Registered Message Shortcut", "callbackID", callbackID)
+}
+
+func (app *Application) handleMessageShortcut(w http.ResponseWriter, blob []byte) {
+	var payload MessageShortcutPayload
+	err := json.Unmarshal(blob, &payload)
+	if err != nil {
+		app.logger.Error("Could not parse MessageShortcutPayload", "error", err.Error())
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	handler, ok := app.shortcuts[payload.CallbackID]
+	if !ok {
+		http.Error(w, "Invalid callback ID", http.StatusBadRequest)
+		return
+	}
+
+	ackChan := make(chan []byte)
+	errChan := make(chan error)
+
+	go func() {
+		req := &MessageShortcutRequest{
+			Payload: payload,
+			baseRequest: baseRequest{
+				errChannel:  errChan,
+				ackChannel:  ackChan,
+				ackCalled:   false,
+				writer:      w,
+				Logger:      app.logger,
+				app:         app,
+				authorize:   payload.authorizeContext(),
+				responseURL: newResponseURL(payload.ResponseURL),
+				context:     payload.requestContext(),
+			},
+		}
+		err := handler(req)
+		if err == nil {
+			return
+		}
+		app.logger.Error("A message shortcut handler failed", "callbackID", payload.CallbackID, "code", ErrorCode(err), "error", err.Error())
+		app.handleError(req, MessageShortcutRequestKind, &req.Payload, err)
+	}()
+
+	select {
+	case <-ackChan:
+		w.Write(nil)
+	case err := <-errChan:
+		if err == nil {
+			return
+		}
+		http.Error(w, "An error occurred", http.StatusInternalServerError)
+		return
+	}
+}
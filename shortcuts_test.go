@@ -0,0 +1,60 @@
+package slap_test
+
+import (
+	"testing"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+func TestMessageShortcut(t *testing.T) {
+	t.Parallel()
+
+	server := newResponseURLServer(200, "ok")
+	defer server.Close()
+
+	payloads := make(chan slap.MessageShortcutPayload, 1)
+	app, router := createTestApp()
+	app.RegisterMessageShortcut("save-message", func(req *slap.MessageShortcutRequest) error {
+		req.Ack()
+		payloads <- req.Payload
+		return req.Respond(slap.ResponseMessage{Text: "Saved!"})
+	})
+
+	tester := slaptest.New(router, "signing-secret")
+	res := tester.MessageShortcut(slaptest.MessageShortcut{
+		CallbackID:  "save-message",
+		ResponseURL: server.URL,
+		Message:     slack.Msg{Timestamp: "1234.5678", Text: "Remember me"},
+	})
+
+	statusGot, statusWant := res.StatusCode, 200
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	payload := <-payloads
+	textGot, textWant := payload.Message.Text, "Remember me"
+	if textGot != textWant {
+		t.Errorf("Unexpected message text, got: %v, want: %v", textGot, textWant)
+	}
+
+	channelGot, channelWant := payload.Channel.ID, slaptest.DefaultChannelID
+	if channelGot != channelWant {
+		t.Errorf("Unexpected channel, got: %v, want: %v", channelGot, channelWant)
+	}
+}
+
+func TestMessageShortcutUnknown(t *testing.T) {
+	t.Parallel()
+
+	_, router := createTestApp()
+	tester := slaptest.New(router, "signing-secret")
+	res := tester.MessageShortcut(slaptest.MessageShortcut{CallbackID: "unknown"})
+
+	statusGot, statusWant := res.StatusCode, 400
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+}
@@ -10,6 +10,14 @@ import (
 	"net/http"
 )
 
+// Derives a key for one purpose from a secret, so that a value signed
+// for one purpose can't be used for another.
+func deriveSecret(secret string, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func (app *Application) validateSignature(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		signature := r.Header.Get("x-slack-signature")
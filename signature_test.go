@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"io"
 	"net/http"
@@ -124,3 +125,37 @@ func TestValidateSignature(t *testing.T) {
 		}
 	})
 }
+
+func TestDeriveSecret(t *testing.T) {
+	t.Parallel()
+
+	app := New(Config{
+		Router: http.NewServeMux(),
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		SigningSecret: "secret",
+		Installations: NewMemoryInstallationStore(),
+		OAuth: &OAuthConfig{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+		},
+	})
+
+	secrets := []string{app.signingSecret, app.metadataSecret, app.oauth.StateSecret}
+	for i := range secrets {
+		for j := i + 1; j < len(secrets); j++ {
+			if secrets[i] == secrets[j] {
+				t.Errorf("Expected a separate key for each purpose, got: %v", secrets)
+			}
+		}
+	}
+
+	signed := "j." + base64.RawURLEncoding.EncodeToString([]byte(`{}`))
+	mac := hmac.New(sha256.New, []byte(app.signingSecret))
+	mac.Write([]byte(signed))
+	signed += "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	if _, _, err := app.verifyMetadata(signed); err != ErrInvalidMetadata {
+		t.Errorf("Unexpected error for metadata signed with the signing secret, got: %v, want: %v", err, ErrInvalidMetadata)
+	}
+}
@@ -2,17 +2,14 @@ package slap_test
 
 import (
 	"bytes"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"time"
 
 	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
 )
 
 func createTestApp() (*slap.Application, *http.ServeMux) {
@@ -27,23 +24,9 @@ func createTestApp() (*slap.Application, *http.ServeMux) {
 }
 
 func addSignatureHeaders(req *http.Request) {
-	body, err := io.ReadAll(req.Body)
-	if err != nil {
-		panic("Could not read body")
+	if err := slaptest.Sign(req, "signing-secret"); err != nil {
+		panic("Could not sign request: " + err.Error())
 	}
-	req.Body = io.NopCloser(bytes.NewBuffer(body))
-
-	ts := fmt.Sprintf("%v", time.Now().UnixMilli())
-	contents := "v0:" + ts + ":" + string(body)
-	hmac := hmac.New(sha256.New, []byte("signing-secret"))
-	_, err = hmac.Write([]byte(contents))
-	if err != nil {
-		panic("Could not write hmac:" + err.Error())
-	}
-
-	signature := "v0=" + hex.EncodeToString(hmac.Sum(nil))
-	req.Header.Add("x-slack-request-timestamp", ts)
-	req.Header.Add("x-slack-signature", signature)
 }
 
 func getJSONTestData(name string) ([]byte, error) {
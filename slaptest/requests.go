@@ -0,0 +1,281 @@
+package slaptest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/slack-go/slack"
+)
+
+// The IDs used when a request field is left empty.
+const (
+	DefaultTeamID    = "T0000001"
+	DefaultUserID    = "U0000001"
+	DefaultChannelID = "C0000001"
+	DefaultAppID     = "A0000001"
+	DefaultTriggerID = "0000001.0000001.test"
+)
+
+// A slash command request.
+type Command struct {
+	// Required. The command, e.g. "/start".
+	Command      string
+	Text         string
+	TeamID       string
+	TeamDomain   string
+	EnterpriseID string
+	ChannelID    string
+	ChannelName  string
+	UserID       string
+	UserName     string
+	ResponseURL  string
+	TriggerID    string
+	APIAppID     string
+}
+
+// Encodes the command as Slack's form body.
+func (c Command) Body() []byte {
+	form := url.Values{}
+	form.Set("command", c.Command)
+	form.Set("text", c.Text)
+	form.Set("team_id", or(c.TeamID, DefaultTeamID))
+	form.Set("team_domain", c.TeamDomain)
+	form.Set("enterprise_id", c.EnterpriseID)
+	form.Set("channel_id", or(c.ChannelID, DefaultChannelID))
+	form.Set("channel_name", c.ChannelName)
+	form.Set("user_id", or(c.UserID, DefaultUserID))
+	form.Set("user_name", c.UserName)
+	form.Set("response_url", c.ResponseURL)
+	form.Set("trigger_id", or(c.TriggerID, DefaultTriggerID))
+	form.Set("api_app_id", or(c.APIAppID, DefaultAppID))
+	return []byte(form.Encode())
+}
+
+// A block_actions interaction request.
+type BlockAction struct {
+	TeamID      string
+	UserID      string
+	ChannelID   string
+	TriggerID   string
+	ResponseURL string
+	APIAppID    string
+	// The user's locale, sent when the app has enabled locales, e.g. "fr-FR".
+	Locale string
+	// Required. The action that was taken, e.g. a button click.
+	Action slack.BlockAction
+	// The message containing the action, if any.
+	Message *slack.Message
+	// The view containing the action, if any.
+	View *slack.View
+	// The state of the input blocks by block ID and action ID.
+	State map[string]map[string]slack.BlockAction
+}
+
+// Encodes the block action as Slack's form body.
+func (a BlockAction) Body() ([]byte, error) {
+	payload := interactionPayload("block_actions", a.TeamID, a.UserID, a.TriggerID, a.APIAppID)
+	payload["actions"] = []slack.BlockAction{a.Action}
+	if a.Locale != "" {
+		payload["user"].(map[string]string)["locale"] = a.Locale
+	}
+	if a.ResponseURL != "" {
+		payload["response_url"] = a.ResponseURL
+	}
+	if a.View != nil {
+		payload["view"] = a.View
+		payload["container"] = map[string]any{"type": "view", "view_id": a.View.ID}
+	} else {
+		channelID := or(a.ChannelID, DefaultChannelID)
+		payload["channel"] = map[string]string{"id": channelID}
+		container := map[string]any{"type": "message", "channel_id": channelID}
+		if a.Message != nil {
+			payload["message"] = a.Message
+			container["message_ts"] = a.Message.Timestamp
+		}
+		payload["container"] = container
+	}
+	if a.State != nil {
+		payload["state"] = map[string]any{"values": a.State}
+	}
+	return formPayload(payload)
+}
+
+// A message_action interaction request, sent when a message shortcut is used.
+type MessageShortcut struct {
+	TeamID      string
+	UserID      string
+	ChannelID   string
+	TriggerID   string
+	ResponseURL string
+	APIAppID    string
+	// Required. The callback ID of the shortcut.
+	CallbackID string
+	// The message the shortcut was used on.
+	Message slack.Msg
+}
+
+// Encodes the message shortcut as Slack's form body.
+func (m MessageShortcut) Body() ([]byte, error) {
+	payload := interactionPayload("message_action", m.TeamID, m.UserID, m.TriggerID, m.APIAppID)
+	payload["callback_id"] = m.CallbackID
+	payload["channel"] = map[string]string{"id": or(m.ChannelID, DefaultChannelID)}
+	payload["message"] = m.Message
+	payload["message_ts"] = m.Message.Timestamp
+	if m.ResponseURL != "" {
+		payload["response_url"] = m.ResponseURL
+	}
+	return formPayload(payload)
+}
+
+// A view_submission interaction request.
+type ViewSubmission struct {
+	TeamID    string
+	UserID    string
+	TriggerID string
+	APIAppID  string
+	// Required. The submitted view. Set View.CallbackID to
+	// route the submission to its handler.
+	View slack.View
+	// The submitted values by block ID and action ID.
+	Values map[string]map[string]slack.BlockAction
+}
+
+// Encodes the view submission as Slack's form body.
+func (v ViewSubmission) Body() ([]byte, error) {
+	payload := interactionPayload("view_submission", v.TeamID, v.UserID, v.TriggerID, v.APIAppID)
+	view := v.View
+	if view.ID == "" {
+		view.ID = "V0000001"
+	}
+	if v.Values != nil {
+		view.State = &slack.ViewState{Values: v.Values}
+	}
+	payload["view"] = view
+	return formPayload(payload)
+}
+
+// A view_closed interaction request, sent when a modal with
+// NotifyOnClose is closed.
+type ViewClosed struct {
+	TeamID   string
+	UserID   string
+	APIAppID string
+	// Required. The closed view. Set View.CallbackID to route
+	// the request to its handler.
+	View slack.View
+	// Whether the whole modal stack was closed.
+	IsCleared bool
+}
+
+// Encodes the view closed request as Slack's form body.
+func (v ViewClosed) Body() ([]byte, error) {
+	payload := interactionPayload("view_closed", v.TeamID, v.UserID, "", v.APIAppID)
+	delete(payload, "trigger_id")
+	view := v.View
+	if view.ID == "" {
+		view.ID = "V0000001"
+	}
+	payload["view"] = view
+	payload["is_cleared"] = v.IsCleared
+	return formPayload(payload)
+}
+
+// An Events API event_callback request.
+type Event struct {
+	TeamID       string
+	EnterpriseID string
+	APIAppID     string
+	EventID      string
+	// The installations the event is delivered for.
+	//
+	// Defaults to a single bot authorization for the team.
+	Authorizations []slap.Authorization
+	// Required. The inner event, e.g. a slack.MessageEvent or a
+	// map with a "type" key.
+	Event any
+}
+
+// Encodes the event as Slack's JSON body.
+func (e Event) Body() ([]byte, error) {
+	teamID := or(e.TeamID, DefaultTeamID)
+	authorizations := e.Authorizations
+	if authorizations == nil {
+		authorizations = []slap.Authorization{{
+			EnterpriseID: e.EnterpriseID,
+			TeamID:       teamID,
+			UserID:       "U0000BOT",
+			IsBot:        true,
+		}}
+	}
+	return json.Marshal(map[string]any{
+		"type":           slap.EventCallback,
+		"team_id":        teamID,
+		"enterprise_id":  e.EnterpriseID,
+		"api_app_id":     or(e.APIAppID, DefaultAppID),
+		"event_id":       or(e.EventID, "Ev0000001"),
+		"event_time":     time.Now().Unix(),
+		"authorizations": authorizations,
+		"event":          e.Event,
+	})
+}
+
+// Adds Slack's signature headers to a request.
+func Sign(r *http.Request, signingSecret string) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewBuffer(body))
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":" + string(body)))
+
+	r.Header.Set("x-slack-request-timestamp", timestamp)
+	r.Header.Set("x-slack-signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+// Creates a signed POST request as Slack would send it.
+func NewRequest(path string, contentType string, body []byte, signingSecret string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	r.Header.Set("content-type", contentType)
+	Sign(r, signingSecret)
+	return r
+}
+
+func interactionPayload(kind string, teamID string, userID string, triggerID string, appID string) map[string]any {
+	teamID = or(teamID, DefaultTeamID)
+	return map[string]any{
+		"type":       kind,
+		"team":       map[string]string{"id": teamID},
+		"user":       map[string]string{"id": or(userID, DefaultUserID), "team_id": teamID},
+		"trigger_id": or(triggerID, DefaultTriggerID),
+		"api_app_id": or(appID, DefaultAppID),
+	}
+}
+
+func formPayload(payload map[string]any) ([]byte, error) {
+	blob, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(url.Values{"payload": {string(blob)}}.Encode()), nil
+}
+
+func or(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,211 @@
+package slaptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A call made to the fake Slack Web API.
+type Call struct {
+	// The Web API method, e.g. "chat.postMessage".
+	Method string
+	// The token the call was made with.
+	Token string
+	// The call's parameters. Objects and arrays in JSON bodies
+	// are re-encoded as JSON strings, e.g. Params.Get("view").
+	Params url.Values
+	// The raw request body.
+	Body []byte
+}
+
+// A function returning the response body for a fake Web API method.
+//
+// The returned value is encoded as JSON.
+type MethodHandler func(call Call) any
+
+// An in-memory fake of the Slack Web API that records every call.
+//
+// Use Server.APIURL as slap.Config.APIURL.
+type Server struct {
+	*httptest.Server
+	mu       sync.Mutex
+	calls    []Call
+	handlers map[string]MethodHandler
+	notify   chan struct{}
+	seq      int
+}
+
+// Starts a fake Slack Web API server.
+//
+// Every method responds with {"ok": true} unless a handler is set
+// with Handle. "chat.*" and "views.*" methods include the channel,
+// timestamp and view fields the Slack client expects.
+func NewServer() *Server {
+	s := &Server{
+		handlers: make(map[string]MethodHandler),
+		notify:   make(chan struct{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// The Web API base URL of the server.
+func (s *Server) APIURL() string {
+	return s.URL + "/api/"
+}
+
+// Sets the response for a Web API method.
+func (s *Server) Handle(method string, handler MethodHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// Returns the calls made to a Web API method, or all calls
+// if method is empty.
+func (s *Server) Calls(method string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var calls []Call
+	for _, call := range s.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Waits for the first call to a Web API method.
+//
+// Handlers often call the Web API after acknowledging Slack's request,
+// so a call may not have been made when the response is received.
+func (s *Server) WaitForCall(method string, timeout time.Duration) (Call, error) {
+	deadline := time.After(timeout)
+	for {
+		s.mu.Lock()
+		notify := s.notify
+		s.mu.Unlock()
+
+		if calls := s.Calls(method); len(calls) > 0 {
+			return calls[0], nil
+		}
+
+		select {
+		case <-notify:
+		case <-deadline:
+			return Call{}, fmt.Errorf("Timed out waiting for a call to %v", method)
+		}
+	}
+}
+
+// Clears the recorded calls.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, "/api/")
+
+	call, err := readCall(method, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.seq++
+	seq := s.seq
+	s.calls = append(s.calls, call)
+	handler, ok := s.handlers[method]
+	close(s.notify)
+	s.notify = make(chan struct{})
+	s.mu.Unlock()
+
+	var res any
+	if ok {
+		res = handler(call)
+	} else {
+		res = defaultResponse(call, seq)
+	}
+
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func readCall(method string, r *http.Request) (Call, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return Call{}, err
+	}
+
+	call := Call{
+		Method: method,
+		Token:  strings.TrimPrefix(r.Header.Get("authorization"), "Bearer "),
+		Params: r.URL.Query(),
+		Body:   body,
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("content-type"))
+	switch mediaType {
+	case "application/json":
+		var fields map[string]json.RawMessage
+		if err = json.Unmarshal(body, &fields); err != nil {
+			return Call{}, err
+		}
+		for key, raw := range fields {
+			var value string
+			if json.Unmarshal(raw, &value) != nil {
+				value = string(raw)
+			}
+			call.Params.Set(key, value)
+		}
+	case "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return Call{}, err
+		}
+		for key, values := range form {
+			call.Params[key] = values
+		}
+	}
+
+	if token := call.Params.Get("token"); token != "" {
+		call.Token = token
+	}
+	return call, nil
+}
+
+func defaultResponse(call Call, seq int) map[string]any {
+	ts := fmt.Sprintf("%v.%06d", time.Now().Unix(), seq)
+	res := map[string]any{"ok": true}
+
+	switch {
+	case call.Method == "chat.postEphemeral":
+		res["message_ts"] = ts
+	case strings.HasPrefix(call.Method, "chat."):
+		res["channel"] = call.Params.Get("channel")
+		res["ts"] = ts
+		if existing := call.Params.Get("ts"); existing != "" {
+			res["ts"] = existing
+		}
+	case strings.HasPrefix(call.Method, "views."):
+		view := map[string]any{}
+		json.Unmarshal([]byte(call.Params.Get("view")), &view)
+		view["id"] = fmt.Sprintf("V%08d", seq)
+		if id := call.Params.Get("view_id"); id != "" {
+			view["id"] = id
+		}
+		res["view"] = view
+	}
+	return res
+}
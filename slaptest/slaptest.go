@@ -0,0 +1,116 @@
+// This package contains utilities for testing Slap Applications:
+// signed request builders, a runner that captures Slap's responses,
+// and a fake Slack Web API.
+package slaptest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+)
+
+// Sends signed Slack requests to a Slap Application's router.
+type Tester struct {
+	// The router passed to slap.Config.
+	Handler http.Handler
+	// The signing secret passed to slap.Config.
+	SigningSecret string
+	// The PathPrefix passed to slap.Config.
+	PathPrefix string
+	// The SingleEndpoint passed to slap.Config, if any.
+	SingleEndpoint string
+}
+
+// The response Slap sent to a request.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// The response body as a string.
+func (r *Response) Text() string {
+	return string(r.Body)
+}
+
+// Decodes a JSON response body, such as a slap.CommandResponseAction.
+func (r *Response) JSON(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Creates a Tester for the router and signing secret of an Application.
+func New(handler http.Handler, signingSecret string) *Tester {
+	return &Tester{
+		Handler:       handler,
+		SigningSecret: signingSecret,
+	}
+}
+
+// Sends a slash command.
+func (t *Tester) Command(c Command) *Response {
+	return t.Do(t.path("/commands"), "application/x-www-form-urlencoded", c.Body())
+}
+
+// Sends a block action. Panics if the payload cannot be encoded.
+func (t *Tester) BlockAction(a BlockAction) *Response {
+	body, err := a.Body()
+	if err != nil {
+		panic(err)
+	}
+	return t.Do(t.path("/interactions"), "application/x-www-form-urlencoded", body)
+}
+
+// Sends a view submission. Panics if the payload cannot be encoded.
+func (t *Tester) ViewSubmission(v ViewSubmission) *Response {
+	body, err := v.Body()
+	if err != nil {
+		panic(err)
+	}
+	return t.Do(t.path("/interactions"), "application/x-www-form-urlencoded", body)
+}
+
+// Sends a view closed request. Panics if the payload cannot be encoded.
+func (t *Tester) ViewClosed(v ViewClosed) *Response {
+	body, err := v.Body()
+	if err != nil {
+		panic(err)
+	}
+	return t.Do(t.path("/interactions"), "application/x-www-form-urlencoded", body)
+}
+
+// Sends a message shortcut. Panics if the payload cannot be encoded.
+func (t *Tester) MessageShortcut(m MessageShortcut) *Response {
+	body, err := m.Body()
+	if err != nil {
+		panic(err)
+	}
+	return t.Do(t.path("/interactions"), "application/x-www-form-urlencoded", body)
+}
+
+// Sends an Events API event. Panics if the payload cannot be encoded.
+func (t *Tester) Event(e Event) *Response {
+	body, err := e.Body()
+	if err != nil {
+		panic(err)
+	}
+	return t.Do(t.path("/events"), "application/json", body)
+}
+
+// Sends a signed request with any body to a path.
+func (t *Tester) Do(path string, contentType string, body []byte) *Response {
+	w := httptest.NewRecorder()
+	t.Handler.ServeHTTP(w, NewRequest(path, contentType, body, t.SigningSecret))
+	res := w.Result()
+	return &Response{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       w.Body.Bytes(),
+	}
+}
+
+func (t *Tester) path(route string) string {
+	if t.SingleEndpoint != "" {
+		route = t.SingleEndpoint
+	}
+	return t.PathPrefix + route
+}
@@ -0,0 +1,176 @@
+package slaptest_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+func createTestApp(server *slaptest.Server) (*slap.Application, *slaptest.Tester) {
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "xoxb-test", nil
+		},
+		APIURL: server.APIURL(),
+	})
+	return app, slaptest.New(router, "signing-secret")
+}
+
+func TestCommand(t *testing.T) {
+	t.Parallel()
+
+	server := slaptest.NewServer()
+	defer server.Close()
+
+	app, tester := createTestApp(server)
+	app.RegisterCommand("/start", func(req *slap.CommandRequest) error {
+		req.AckWithAction(slap.CommandResponseAction{
+			ResponseType: slap.RespondEphemeral,
+			Text:         "Starting",
+		})
+		_, _, err := req.Client().PostMessage(req.Payload.ChannelID, slack.MsgOptionText("You said: "+req.Payload.Text, false))
+		return err
+	})
+
+	res := tester.Command(slaptest.Command{Command: "/start", Text: "hello"})
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	var action slap.CommandResponseAction
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	textGot, textWant := action.Text, "Starting"
+	if textGot != textWant {
+		t.Errorf("Unexpected response text, got: %v, want: %v", textGot, textWant)
+	}
+
+	call, err := server.WaitForCall("chat.postMessage", time.Second)
+	if err != nil {
+		t.Fatalf("%v", err.Error())
+	}
+
+	messageGot, messageWant := call.Params.Get("text"), "You said: hello"
+	if messageGot != messageWant {
+		t.Errorf("Unexpected message, got: %v, want: %v", messageGot, messageWant)
+	}
+
+	channelGot, channelWant := call.Params.Get("channel"), slaptest.DefaultChannelID
+	if channelGot != channelWant {
+		t.Errorf("Unexpected channel, got: %v, want: %v", channelGot, channelWant)
+	}
+
+	tokenGot, tokenWant := call.Token, "xoxb-test"
+	if tokenGot != tokenWant {
+		t.Errorf("Unexpected token, got: %v, want: %v", tokenGot, tokenWant)
+	}
+}
+
+func TestBlockAction(t *testing.T) {
+	t.Parallel()
+
+	server := slaptest.NewServer()
+	defer server.Close()
+
+	app, tester := createTestApp(server)
+	app.RegisterBlockAction("start-button", func(req *slap.BlockActionRequest) error {
+		req.Ack()
+		_, err := req.Client().OpenView(req.Payload.TriggerID, slack.ModalViewRequest{
+			Type:       "modal",
+			CallbackID: "form-modal",
+			Title:      slack.NewTextBlockObject("plain_text", "Form", false, false),
+		})
+		return err
+	})
+
+	res := tester.BlockAction(slaptest.BlockAction{
+		Action: slack.BlockAction{ActionID: "start-button"},
+	})
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	call, err := server.WaitForCall("views.open", time.Second)
+	if err != nil {
+		t.Fatalf("%v", err.Error())
+	}
+
+	triggerGot, triggerWant := call.Params.Get("trigger_id"), slaptest.DefaultTriggerID
+	if triggerGot != triggerWant {
+		t.Errorf("Unexpected trigger ID, got: %v, want: %v", triggerGot, triggerWant)
+	}
+}
+
+func TestViewSubmission(t *testing.T) {
+	t.Parallel()
+
+	server := slaptest.NewServer()
+	defer server.Close()
+
+	app, tester := createTestApp(server)
+	app.RegisterViewSubmission("form-modal", func(req *slap.ViewSubmissionRequest) error {
+		name := req.Payload.View.State.Values["name-block"]["name"].Value
+		req.AckWithAction(slap.ViewResponseAction{
+			ResponseAction: slap.ViewResponseErrors,
+			Errors:         map[string]string{"name-block": "Invalid name: " + name},
+		})
+		return nil
+	})
+
+	res := tester.ViewSubmission(slaptest.ViewSubmission{
+		View: slack.View{CallbackID: "form-modal"},
+		Values: map[string]map[string]slack.BlockAction{
+			"name-block": {"name": {Value: "Bob"}},
+		},
+	})
+
+	var action slap.ViewResponseAction
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	errorGot, errorWant := action.Errors["name-block"], "Invalid name: Bob"
+	if errorGot != errorWant {
+		t.Errorf("Unexpected error, got: %v, want: %v", errorGot, errorWant)
+	}
+}
+
+func TestEvent(t *testing.T) {
+	t.Parallel()
+
+	server := slaptest.NewServer()
+	defer server.Close()
+
+	app, tester := createTestApp(server)
+	app.RegisterEventHandler("app_mention", func(req *slap.EventRequest) error {
+		req.Ack()
+		_, _, err := req.Client().PostMessage("C0123456", slack.MsgOptionText("Hi!", false))
+		return err
+	})
+
+	res := tester.Event(slaptest.Event{
+		Event: map[string]string{"type": "app_mention", "user": "U0123456", "text": "<@U0000BOT>"},
+	})
+
+	statusGot, statusWant := res.StatusCode, http.StatusOK
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	if _, err := server.WaitForCall("chat.postMessage", time.Second); err != nil {
+		t.Errorf("%v", err.Error())
+	}
+}
@@ -0,0 +1,325 @@
+// This package renders Block Kit JSON and YAML templates, loaded from
+// an fs.FS, into blocks and views.
+//
+// Templates are JSON or YAML files with text/template placeholders:
+//
+//	{
+//	  "blocks": [
+//	    {"type": "section", "text": {"type": "mrkdwn", "text": "Welcome, *{{.Name}}*!"}}
+//	  ]
+//	}
+//
+// The output of every placeholder has "&", "<" and ">" escaped and is
+// escaped for a JSON string, so data can't add mentions or links or
+// change the structure of the template. Formatting characters such as
+// "*", "_", "~" and "`" are not escaped, so data can still format its
+// own text. Use the raw function to skip the escaping, e.g.
+// {{raw .Summary}}, and the user, channel and link functions to
+// create mentions and links.
+//
+// Templates ending in ".yaml" or ".yml" are YAML, and are converted to
+// JSON after rendering:
+//
+//	blocks:
+//	  - type: section
+//	    text:
+//	      type: mrkdwn
+//	      text: "Welcome, *{{.Name}}*!"
+//
+// The values of placeholders in YAML are inserted after the YAML is
+// parsed, so they are always part of a single scalar. Quote
+// placeholders that should be strings, as unquoted values like "true"
+// or "42" are resolved to bools and numbers. Only the first document
+// of a YAML file is rendered.
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/slack-go/slack"
+)
+
+// The name of the function added to every placeholder
+const escapeFunc = "_escape"
+
+// A template error with its file and line
+type Error struct {
+	// The template's file name
+	Name string
+	// The line of the error in the template file, or 0 if unknown
+	Line int
+	// The line of a JSON or YAML error in the rendered output, or 0.
+	// It differs from the template's line when placeholders such as
+	// {{range}} or {{template}} add lines.
+	RenderedLine int
+	Err          error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line != 0:
+		return fmt.Sprintf("%v:%v: %v", e.Name, e.Line, e.Err.Error())
+	case e.RenderedLine != 0:
+		return fmt.Sprintf("%v: rendered line %v: %v", e.Name, e.RenderedLine, e.Err.Error())
+	}
+	return fmt.Sprintf("%v: %v", e.Name, e.Err.Error())
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Text that is not escaped for mrkdwn
+type rawText string
+
+// A set of templates loaded from a file system. Templates are parsed
+// when first rendered and cached.
+type Templates struct {
+	fsys  fs.FS
+	funcs template.FuncMap
+	mu    sync.Mutex
+	cache map[string]*template.Template
+}
+
+// Creates a set of templates loaded from a file system, e.g. an
+// embed.FS or os.DirFS("templates").
+func New(fsys fs.FS) *Templates {
+	return &Templates{
+		fsys: fsys,
+		funcs: template.FuncMap{
+			"raw":     func(v any) rawText { return rawText(fmt.Sprint(v)) },
+			"user":    func(id string) rawText { return rawText("<@" + escapeMarkdown(id) + ">") },
+			"channel": func(id string) rawText { return rawText("<#" + escapeMarkdown(id) + ">") },
+			"link": func(url string, text string) rawText {
+				return rawText("<" + escapeMarkdown(url) + "|" + escapeMarkdown(text) + ">")
+			},
+			escapeFunc: escape,
+		},
+		cache: make(map[string]*template.Template),
+	}
+}
+
+// Adds functions for the templates to use. Cached templates are
+// parsed again with the new functions when next rendered.
+//
+// Panics if a function is named "_escape", which is reserved.
+func (t *Templates) Funcs(funcs template.FuncMap) *Templates {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	merged := make(template.FuncMap, len(t.funcs)+len(funcs))
+	for name, fn := range t.funcs {
+		merged[name] = fn
+	}
+	for name, fn := range funcs {
+		if name == escapeFunc {
+			panic(fmt.Sprintf("templates: the function name %v is reserved", escapeFunc))
+		}
+		merged[name] = fn
+	}
+	t.funcs = merged
+	t.cache = make(map[string]*template.Template)
+	return t
+}
+
+// Clears the cached templates, so they are loaded again when next
+// rendered. Useful when editing templates during development.
+func (t *Templates) Reload() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cache = make(map[string]*template.Template)
+}
+
+// Renders a template to JSON. YAML templates are converted to JSON.
+func (t *Templates) Render(name string, data any) ([]byte, error) {
+	tmpl, err := t.load(name)
+	if err != nil {
+		return nil, err
+	}
+	if isYAML(name) {
+		document, err := renderYAML(name, tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		rendered, err := json.Marshal(document)
+		if err != nil {
+			return nil, &Error{Name: name, Err: err}
+		}
+		return rendered, nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, templateError(name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Renders a template of blocks, for a message or view. The template
+// is either an array of blocks or an object with a "blocks" array.
+func (t *Templates) Blocks(name string, data any) ([]slack.Block, error) {
+	rendered, err := t.Render(name, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+	if err := json.Unmarshal(rendered, &raw); err != nil {
+		return nil, jsonError(name, rendered, err)
+	}
+	if raw[0] == '{' {
+		var message struct {
+			Blocks json.RawMessage `json:"blocks"`
+		}
+		if err := json.Unmarshal(raw, &message); err != nil {
+			return nil, &Error{Name: name, Err: err}
+		}
+		if message.Blocks == nil {
+			return nil, &Error{Name: name, Err: errors.New("missing \"blocks\" array")}
+		}
+		raw = message.Blocks
+	}
+
+	var blocks slack.Blocks
+	if err := json.Unmarshal(raw, &blocks); err != nil {
+		return nil, &Error{Name: name, Err: err}
+	}
+	return blocks.BlockSet, nil
+}
+
+// Renders a template of a modal view.
+func (t *Templates) View(name string, data any) (slack.ModalViewRequest, error) {
+	rendered, err := t.Render(name, data)
+	if err != nil {
+		return slack.ModalViewRequest{}, err
+	}
+	var view slack.ModalViewRequest
+	if err := json.Unmarshal(rendered, &view); err != nil {
+		return slack.ModalViewRequest{}, jsonError(name, rendered, err)
+	}
+	if view.Type == "" {
+		view.Type = slack.VTModal
+	}
+	return view, nil
+}
+
+// Loads and parses a template, or returns it from the cache.
+func (t *Templates) load(name string) (*template.Template, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tmpl, ok := t.cache[name]; ok {
+		return tmpl, nil
+	}
+
+	text, err := fs.ReadFile(t.fsys, name)
+	if err != nil {
+		return nil, &Error{Name: name, Err: err}
+	}
+	tmpl, err := template.New(name).Funcs(t.funcs).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return nil, templateError(name, err)
+	}
+	for _, tree := range tmpl.Templates() {
+		if tree.Tree != nil {
+			escapeNode(tree.Tree, tree.Tree.Root)
+		}
+	}
+	t.cache[name] = tmpl
+	return tmpl, nil
+}
+
+// Adds the escape function to the end of every placeholder.
+func escapeNode(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			escapeNode(tree, child)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(escapeFunc).SetTree(tree).SetPos(n.Pos)},
+		})
+	case *parse.IfNode:
+		escapeNode(tree, n.List)
+		escapeNode(tree, n.ElseList)
+	case *parse.RangeNode:
+		escapeNode(tree, n.List)
+		escapeNode(tree, n.ElseList)
+	case *parse.WithNode:
+		escapeNode(tree, n.List)
+		escapeNode(tree, n.ElseList)
+	}
+}
+
+// Escapes the "&", "<" and ">" in a placeholder's value, unless it is
+// raw, and escapes it for a JSON string.
+func escape(v any) string {
+	var text string
+	if raw, ok := v.(rawText); ok {
+		text = string(raw)
+	} else {
+		text = escapeMarkdown(fmt.Sprint(v))
+	}
+	quoted, _ := json.Marshal(text)
+	return string(quoted[1 : len(quoted)-1])
+}
+
+var markdownEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Escapes the characters Slack uses for mentions and links.
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+var templateErrorPattern = regexp.MustCompile(`^template: [^:]+:(\d+)(?::\d+)?: (?:executing "[^"]*" at <[^>]*>: )?(.*)$`)
+
+// Converts a text/template error to an Error with its line.
+func templateError(name string, err error) error {
+	match := templateErrorPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return &Error{Name: name, Err: err}
+	}
+	line, _ := strconv.Atoi(match[1])
+	return &Error{Name: name, Line: line, Err: errors.New(match[2])}
+}
+
+// Converts a JSON error in a rendered template to an Error with
+// its line in the rendered output.
+func jsonError(name string, rendered []byte, err error) error {
+	if isYAML(name) {
+		// The JSON was converted from YAML, so has no useful line
+		return &Error{Name: name, Err: err}
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var offset int64
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return &Error{Name: name, Err: err}
+	}
+	if offset > int64(len(rendered)) {
+		offset = int64(len(rendered))
+	}
+	line := bytes.Count(rendered[:offset], []byte("\n")) + 1
+	return &Error{Name: name, RenderedLine: line, Err: fmt.Errorf("invalid JSON: %w", err)}
+}
@@ -0,0 +1,318 @@
+package templates_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jacob-ian/slap/templates"
+	"github.com/slack-go/slack"
+)
+
+var testFS = fstest.MapFS{
+	"welcome.json": {Data: []byte(`{
+  "blocks": [
+    {"type": "header", "text": {"type": "plain_text", "text": "Welcome"}},
+    {"type": "section", "text": {"type": "mrkdwn", "text": "Hi {{user .UserID}}, meet *{{.Name}}*"}}
+    {{- range .Links}},
+    {"type": "section", "text": {"type": "mrkdwn", "text": "{{link .URL .Title}}"}}
+    {{- end}}
+  ]
+}`)},
+	"list.json": {Data: []byte(`[{"type": "divider"}, {"type": "section", "text": {"type": "mrkdwn", "text": "{{raw .}}"}}]`)},
+	"modal.json": {Data: []byte(`{
+  "title": {"type": "plain_text", "text": "{{.Title}}"},
+  "callback_id": "ticket-modal",
+  "blocks": []
+}`)},
+	"broken.json": {Data: []byte(`[
+  {"type": "divider"},
+  {"type": "divider",}
+]`)},
+	"unknown.json": {Data: []byte(`[
+  {"type": "section", "text": {"type": "mrkdwn", "text": "{{shout .}}"}}
+]`)},
+	"missing.json": {Data: []byte(`[
+  {"type": "divider"},
+  {"type": "section", "text": {"type": "mrkdwn", "text": "{{.Nope}}"}}
+]`)},
+	"welcome.yaml": {Data: []byte(`# A welcome message
+blocks:
+  - type: header
+    text: {type: plain_text, text: "Welcome"}
+  - type: section
+    text:
+      type: mrkdwn
+      text: Hi {{user .UserID}}, meet *{{.Name}}*
+{{- range .Links}}
+  - type: section
+    text: {"type": "mrkdwn", "text": "{{link .URL .Title}}"}
+{{- end}}
+  - type: context
+    elements:
+    - type: mrkdwn
+      text: |-
+        Line one # not a comment
+        Line two
+`)},
+	"modal.yml": {Data: []byte(`title:
+  type: plain_text
+  text: '{{.Title}}'
+callback_id: ticket-modal
+blocks:
+- type: input
+  label: {type: plain_text, text: Title}
+  element:
+    type: plain_text_input
+    action_id: title
+    max_length: {{.Max}}
+`)},
+	"notes.yaml": {Data: []byte(`- type: section
+  block_id: {{.Due}}
+  text:
+    type: plain_text
+    text: Due {{.Due}}, a long note
+      continued on the next line
+- &divider {type: divider}
+- *divider
+`)},
+	"broken.yaml": {Data: []byte(`blocks:
+  - type: divider
+     text: nope
+`)},
+}
+
+type link struct {
+	URL   string
+	Title string
+}
+
+func TestBlocks(t *testing.T) {
+	t.Parallel()
+
+	tmpl := templates.New(testFS)
+	blocks, err := tmpl.Blocks("welcome.json", map[string]any{
+		"UserID": "U0123456",
+		"Name":   `<!channel> "Bobby" & co`,
+		"Links":  []link{{URL: "https://example.com", Title: "Docs"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	countGot, countWant := len(blocks), 3
+	if countGot != countWant {
+		t.Fatalf("Unexpected number of blocks, got: %v, want: %v", countGot, countWant)
+	}
+
+	textGot := blocks[1].(*slack.SectionBlock).Text.Text
+	textWant := `Hi <@U0123456>, meet *&lt;!channel&gt; "Bobby" &amp; co*`
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %v, want: %v", textGot, textWant)
+	}
+
+	linkGot, linkWant := blocks[2].(*slack.SectionBlock).Text.Text, "<https://example.com|Docs>"
+	if linkGot != linkWant {
+		t.Errorf("Unexpected link, got: %v, want: %v", linkGot, linkWant)
+	}
+}
+
+func TestBlocksArray(t *testing.T) {
+	t.Parallel()
+
+	tmpl := templates.New(testFS)
+	blocks, err := tmpl.Blocks("list.json", "*bold*\n<https://example.com>")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	textGot, textWant := blocks[1].(*slack.SectionBlock).Text.Text, "*bold*\n<https://example.com>"
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %v, want: %v", textGot, textWant)
+	}
+}
+
+func TestView(t *testing.T) {
+	t.Parallel()
+
+	tmpl := templates.New(testFS)
+	view, err := tmpl.View("modal.json", map[string]string{"Title": "New ticket"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	titleGot, titleWant := view.Title.Text, "New ticket"
+	if titleGot != titleWant {
+		t.Errorf("Unexpected title, got: %v, want: %v", titleGot, titleWant)
+	}
+
+	typeGot, typeWant := view.Type, slack.VTModal
+	if typeGot != typeWant {
+		t.Errorf("Unexpected type, got: %v, want: %v", typeGot, typeWant)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		line         int
+		renderedLine int
+	}{
+		{"broken.json", 0, 3},
+		{"unknown.json", 2, 0},
+		{"missing.json", 3, 0},
+	}
+
+	tmpl := templates.New(testFS)
+	for _, test := range tests {
+		_, err := tmpl.Blocks(test.name, map[string]string{})
+		var templateErr *templates.Error
+		if !errors.As(err, &templateErr) {
+			t.Errorf("Expected a templates.Error for %v, got: %v", test.name, err)
+			continue
+		}
+
+		lineGot, lineWant := templateErr.Line, test.line
+		if lineGot != lineWant {
+			t.Errorf("Unexpected line for %v, got: %v, want: %v (%v)", test.name, lineGot, lineWant, err)
+		}
+		renderedGot, renderedWant := templateErr.RenderedLine, test.renderedLine
+		if renderedGot != renderedWant {
+			t.Errorf("Unexpected rendered line for %v, got: %v, want: %v (%v)", test.name, renderedGot, renderedWant, err)
+		}
+		if !strings.HasPrefix(err.Error(), test.name+":") {
+			t.Errorf("Expected the error to start with the file name, got: %v", err)
+		}
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	t.Parallel()
+
+	tmpl := templates.New(testFS).Funcs(map[string]any{"shout": strings.ToUpper})
+	blocks, err := tmpl.Blocks("unknown.json", "hello")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	textGot, textWant := blocks[0].(*slack.SectionBlock).Text.Text, "HELLO"
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %v, want: %v", textGot, textWant)
+	}
+}
+
+func TestBlocksYAML(t *testing.T) {
+	t.Parallel()
+
+	tmpl := templates.New(testFS)
+	blocks, err := tmpl.Blocks("welcome.yaml", map[string]any{
+		"UserID": "U0123456",
+		"Name":   "<!channel> \"Bobby\": & co\n  - type: divider # x",
+		"Links":  []link{{URL: "https://example.com", Title: "Docs"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	countGot, countWant := len(blocks), 4
+	if countGot != countWant {
+		t.Fatalf("Unexpected number of blocks, got: %v, want: %v", countGot, countWant)
+	}
+
+	textGot := blocks[1].(*slack.SectionBlock).Text.Text
+	textWant := "Hi <@U0123456>, meet *&lt;!channel&gt; \"Bobby\": &amp; co\n  - type: divider # x*"
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %q, want: %q", textGot, textWant)
+	}
+
+	linkGot, linkWant := blocks[2].(*slack.SectionBlock).Text.Text, "<https://example.com|Docs>"
+	if linkGot != linkWant {
+		t.Errorf("Unexpected link, got: %v, want: %v", linkGot, linkWant)
+	}
+
+	contextGot := blocks[3].(*slack.ContextBlock).ContextElements.Elements[0].(*slack.TextBlockObject).Text
+	contextWant := "Line one # not a comment\nLine two"
+	if contextGot != contextWant {
+		t.Errorf("Unexpected context, got: %q, want: %q", contextGot, contextWant)
+	}
+}
+
+func TestViewYAML(t *testing.T) {
+	t.Parallel()
+
+	tmpl := templates.New(testFS)
+	view, err := tmpl.View("modal.yml", map[string]any{"Title": "It's new", "Max": 80})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	titleGot, titleWant := view.Title.Text, "It's new"
+	if titleGot != titleWant {
+		t.Errorf("Unexpected title, got: %v, want: %v", titleGot, titleWant)
+	}
+
+	input := view.Blocks.BlockSet[0].(*slack.InputBlock)
+	maxGot, maxWant := input.Element.(*slack.PlainTextInputBlockElement).MaxLength, 80
+	if maxGot != maxWant {
+		t.Errorf("Unexpected max length, got: %v, want: %v", maxGot, maxWant)
+	}
+}
+
+func TestBlocksYAMLScalars(t *testing.T) {
+	t.Parallel()
+
+	tmpl := templates.New(testFS)
+	blocks, err := tmpl.Blocks("notes.yaml", map[string]any{"Due": "2024-01-31"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err.Error())
+	}
+
+	countGot, countWant := len(blocks), 3
+	if countGot != countWant {
+		t.Fatalf("Unexpected number of blocks, got: %v, want: %v", countGot, countWant)
+	}
+
+	section := blocks[0].(*slack.SectionBlock)
+	textGot, textWant := section.Text.Text, "Due 2024-01-31, a long note continued on the next line"
+	if textGot != textWant {
+		t.Errorf("Unexpected text, got: %q, want: %q", textGot, textWant)
+	}
+
+	idGot, idWant := section.BlockID, "2024-01-31"
+	if idGot != idWant {
+		t.Errorf("Unexpected block ID, got: %v, want: %v", idGot, idWant)
+	}
+}
+
+func TestYAMLError(t *testing.T) {
+	t.Parallel()
+
+	tmpl := templates.New(testFS)
+	_, err := tmpl.Blocks("broken.yaml", nil)
+	var templateErr *templates.Error
+	if !errors.As(err, &templateErr) {
+		t.Fatalf("Expected a templates.Error, got: %v", err)
+	}
+
+	lineGot, lineWant := templateErr.RenderedLine, 3
+	if lineGot != lineWant {
+		t.Errorf("Unexpected rendered line, got: %v, want: %v (%v)", lineGot, lineWant, err)
+	}
+	if !strings.HasPrefix(err.Error(), "broken.yaml: rendered line 3: ") {
+		t.Errorf("Expected the rendered line in the error, got: %v", err)
+	}
+}
+
+func TestFuncsReservedName(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a reserved function name")
+		}
+	}()
+	templates.New(testFS).Funcs(map[string]any{"_escape": strings.ToUpper})
+}
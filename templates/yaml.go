@@ -0,0 +1,155 @@
+package templates
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Whether a template is YAML, from its file extension.
+func isYAML(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
+
+// Renders a YAML template and converts it to JSON.
+//
+// Placeholders are rendered as unique tokens, which are replaced with
+// their values after the YAML is parsed, so data can't change the
+// structure of the template in any YAML context.
+func renderYAML(name string, tmpl *template.Template, data any) (any, error) {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, &Error{Name: name, Err: err}
+	}
+	values := &yamlValues{prefix: "slap" + hex.EncodeToString(nonce) + "_"}
+
+	clone, err := tmpl.Clone()
+	if err != nil {
+		return nil, &Error{Name: name, Err: err}
+	}
+	clone.Funcs(template.FuncMap{escapeFunc: values.add})
+
+	var buf strings.Builder
+	if err := clone.Execute(&buf, data); err != nil {
+		return nil, templateError(name, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(buf.String()), &root); err != nil {
+		return nil, yamlError(name, err)
+	}
+	values.replaceNode(&root)
+	var document any
+	if err := root.Decode(&document); err != nil {
+		return nil, yamlError(name, err)
+	}
+	return jsonValue(document), nil
+}
+
+// The values of a YAML template's placeholders
+type yamlValues struct {
+	prefix string
+	values []string
+}
+
+// Stores a placeholder's value, escaped for mrkdwn unless it is raw,
+// and returns its token.
+func (v *yamlValues) add(value any) string {
+	text := ""
+	if raw, ok := value.(rawText); ok {
+		text = string(raw)
+	} else {
+		text = escapeMarkdown(fmt.Sprint(value))
+	}
+	v.values = append(v.values, text)
+	return v.prefix + strconv.Itoa(len(v.values)-1) + "_"
+}
+
+// Replaces the tokens in a string with their values.
+func (v *yamlValues) replace(s string) string {
+	if !strings.Contains(s, v.prefix) {
+		return s
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, v.prefix)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:i])
+		s = s[i+len(v.prefix):]
+		j := strings.IndexByte(s, '_')
+		n, err := strconv.Atoi(s[:max(j, 0)])
+		if j < 0 || err != nil || n >= len(v.values) {
+			b.WriteString(v.prefix)
+			continue
+		}
+		b.WriteString(v.values[n])
+		s = s[j+1:]
+	}
+}
+
+// Replaces the tokens in a node's scalars. Plain scalars are resolved
+// again with their values, e.g. to numbers, but timestamps are kept as
+// strings.
+func (v *yamlValues) replaceNode(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		if value := v.replace(node.Value); value != node.Value {
+			node.Value = value
+			if node.Style == 0 {
+				node.Tag = ""
+			}
+		}
+		if node.ShortTag() == "!!timestamp" {
+			node.Tag = "!!str"
+		}
+	}
+	for _, child := range node.Content {
+		v.replaceNode(child)
+	}
+}
+
+// Converts decoded YAML to values that can be marshalled to JSON,
+// whose object keys must be strings.
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = jsonValue(item)
+		}
+		return v
+	case map[any]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			object[fmt.Sprint(key)] = jsonValue(item)
+		}
+		return object
+	case []any:
+		for i, item := range v {
+			v[i] = jsonValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+var yamlErrorPattern = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// Converts a YAML error to an Error with its line in the rendered
+// output.
+func yamlError(name string, err error) error {
+	match := yamlErrorPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return &Error{Name: name, Err: fmt.Errorf("invalid YAML: %w", err)}
+	}
+	line, _ := strconv.Atoi(match[1])
+	return &Error{Name: name, RenderedLine: line, Err: fmt.Errorf("invalid YAML: %v", match[2])}
+}
@@ -0,0 +1,54 @@
+package slap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Returned by UserClient when the user has not authorized the app
+// with user scopes.
+var ErrUserNotAuthorized = errors.New("User has not authorized the app")
+
+// Returned by UserClient when neither Config.UserToken
+// nor Config.Installations has been set.
+var ErrNoUserTokenSource = errors.New("No user token source has been configured")
+
+// A function that returns the user token of AuthorizeContext.UserID.
+//
+// Return an empty token or ErrUserNotAuthorized if the user has
+// not authorized the app.
+type UserTokenResolver func(ctx AuthorizeContext) (string, error)
+
+// Resolves the token of the user who triggered a request.
+func (app *Application) resolveUserToken(ctx AuthorizeContext) (string, error) {
+	if app.userToken == nil {
+		return "", ErrNoUserTokenSource
+	}
+	if ctx.UserID == "" {
+		return "", fmt.Errorf("%w: request has no user", ErrUserNotAuthorized)
+	}
+	token, err := app.userToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", ErrUserNotAuthorized
+	}
+	return token, nil
+}
+
+// Reads a user's token from the InstallationStore,
+// refreshing it first if it is about to expire.
+func (app *Application) installationUserToken(ctx AuthorizeContext) (string, error) {
+	query := ctx.installationQuery()
+	query.UserID = ctx.UserID
+
+	installation, err := app.findInstallation(query)
+	if errors.Is(err, ErrInstallationNotFound) {
+		return "", ErrUserNotAuthorized
+	}
+	if err != nil {
+		return "", err
+	}
+	return installation.UserToken, nil
+}
@@ -0,0 +1,81 @@
+package slap_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+)
+
+func userClientError(t *testing.T, store slap.InstallationStore) error {
+	router := http.NewServeMux()
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		Installations: store,
+	})
+
+	errs := make(chan error, 1)
+	app.RegisterCommand("/help", func(req *slap.CommandRequest) error {
+		req.Ack()
+		_, err := req.UserClient()
+		errs <- err
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/commands", bytes.NewReader(testCommandBody()))
+	r.Header.Add("content-type", "application/x-www-form-urlencoded")
+	addSignatureHeaders(r)
+	router.ServeHTTP(w, r)
+
+	return <-errs
+}
+
+func TestUserClientAuthorized(t *testing.T) {
+	t.Parallel()
+
+	store := slap.NewMemoryInstallationStore()
+	store.Save(slap.Installation{
+		TeamID:    "T0123456",
+		BotToken:  "xoxb-test",
+		UserID:    "U0123456",
+		UserToken: "xoxp-test",
+	})
+
+	if err := userClientError(t, store); err != nil {
+		t.Errorf("Unexpected error: %v", err.Error())
+	}
+}
+
+func TestUserClientNotAuthorized(t *testing.T) {
+	t.Parallel()
+
+	store := slap.NewMemoryInstallationStore()
+	store.Save(slap.Installation{
+		TeamID:    "T0123456",
+		BotToken:  "xoxb-test",
+		UserID:    "U9999999",
+		UserToken: "xoxp-test",
+	})
+
+	err := userClientError(t, store)
+	if !errors.Is(err, slap.ErrUserNotAuthorized) {
+		t.Errorf("Unexpected error, got: %v, want: %v", err, slap.ErrUserNotAuthorized)
+	}
+}
+
+func TestUserClientNoSource(t *testing.T) {
+	t.Parallel()
+
+	err := userClientError(t, nil)
+	if !errors.Is(err, slap.ErrNoUserTokenSource) {
+		t.Errorf("Unexpected error, got: %v, want: %v", err, slap.ErrNoUserTokenSource)
+	}
+}
@@ -0,0 +1,335 @@
+package slap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/slack-go/slack"
+)
+
+// A validation rule for the values of an input element. Returns an
+// error with the message to show below the input if the values are
+// invalid.
+//
+// Values is empty when nothing was entered or selected, and has one
+// value for single value elements.
+type Rule func(values []string) error
+
+// Validation rules for a view's inputs, keyed by "blockID/actionID"
+type Validator map[string][]Rule
+
+// Requires a value to be entered or selected.
+func Required() Rule {
+	return func(values []string) error {
+		if len(values) == 0 {
+			return errors.New("This field is required.")
+		}
+		return nil
+	}
+}
+
+// Requires each value to be between min and max characters long.
+// A max of 0 means no maximum.
+func Length(min int, max int) Rule {
+	return func(values []string) error {
+		for _, value := range values {
+			length := utf8.RuneCountInString(value)
+			if length < min {
+				return fmt.Errorf("Must be at least %v characters.", min)
+			}
+			if max > 0 && length > max {
+				return fmt.Errorf("Must be at most %v characters.", max)
+			}
+		}
+		return nil
+	}
+}
+
+// Requires each value to be a number between min and max.
+func Range(min float64, max float64) Rule {
+	return func(values []string) error {
+		for _, value := range values {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return errors.New("Enter a number.")
+			}
+			if n < min || n > max {
+				return fmt.Errorf("Must be between %v and %v.", min, max)
+			}
+		}
+		return nil
+	}
+}
+
+// Requires between min and max values to be selected.
+// A max of 0 means no maximum.
+func Count(min int, max int) Rule {
+	return func(values []string) error {
+		if len(values) == 0 {
+			return nil
+		}
+		if len(values) < min {
+			return fmt.Errorf("Select at least %v.", min)
+		}
+		if max > 0 && len(values) > max {
+			return fmt.Errorf("Select at most %v.", max)
+		}
+		return nil
+	}
+}
+
+// Requires each value to match a regular expression, showing the
+// message if it doesn't.
+func Match(pattern *regexp.Regexp, message string) Rule {
+	return func(values []string) error {
+		for _, value := range values {
+			if !pattern.MatchString(value) {
+				return errors.New(message)
+			}
+		}
+		return nil
+	}
+}
+
+// Validates a view's state, returning error messages by block ID.
+func (v Validator) validate(state map[string]map[string]slack.BlockAction) map[string]string {
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+	// Sorted so the same error is shown when several rules fail
+	sort.Strings(keys)
+
+	fields := make(map[string]string)
+	for _, key := range keys {
+		rules := v[key]
+		blockID, actionID, _ := strings.Cut(key, "/")
+		if _, ok := fields[blockID]; ok {
+			continue
+		}
+		values := []string{}
+		if action, ok := state[blockID][actionID]; ok {
+			values = stringsValue(action)
+		}
+		for _, rule := range rules {
+			if err := rule(values); err != nil {
+				fields[blockID] = err.Error()
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// Registers validation rules for a view submission's inputs, which
+// are checked before its handler is called. Invalid submissions are
+// acknowledged with their errors shown below the inputs, and the
+// handler is not called.
+//
+// Panics if the callbackID already has a Validator or a key is not
+// of the form "blockID/actionID".
+func (app *Application) RegisterViewValidator(callbackID string, validator Validator) {
+	_, ok := app.viewValidators[callbackID]
+	if ok {
+		panic(fmt.Sprintf("View Validator for Callback ID %v has already been registered", callbackID))
+	}
+	for key := range validator {
+		blockID, actionID, ok := strings.Cut(key, "/")
+		if !ok || blockID == "" || actionID == "" {
+			panic(fmt.Sprintf("Invalid View Validator key %q, expected \"blockID/actionID\"", key))
+		}
+	}
+	app.viewValidators[callbackID] = validator
+	app.logger.Info("Registered View Validator", "callbackID", callbackID)
+}
+
+// Runs a view submission's Validator. Returns true if the submission
+// was invalid and has been acknowledged with its errors.
+func (app *Application) rejectInvalidSubmission(w http.ResponseWriter, payload ViewSubmissionPayload) bool {
+	validator, ok := app.viewValidators[payload.View.CallbackID]
+	if !ok {
+		return false
+	}
+	var state map[string]map[string]slack.BlockAction
+	if payload.View.State != nil {
+		state = payload.View.State.Values
+	}
+	fields := validator.validate(state)
+	if len(fields) == 0 {
+		return false
+	}
+
+	bytes, err := json.Marshal(ViewResponseAction{
+		ResponseAction: ViewResponseErrors,
+		Errors:         fields,
+	})
+	if err != nil {
+		app.logger.Error("Could not encode view response action", "error", err.Error())
+		http.Error(w, "An error occurred", http.StatusInternalServerError)
+		return true
+	}
+	w.Header().Set("content-type", "application/json")
+	w.Write(bytes)
+	return true
+}
+
+// Creates a ViewSubmissionHandler that binds the submission into a
+// form struct and validates it before calling handler. Invalid
+// submissions are acknowledged with their errors shown below the
+// inputs, and handler is not called. See BindState.
+//
+//	app.RegisterViewSubmission("ticket-modal", slap.FormHandler(
+//		func(req *slap.ViewSubmissionRequest, form TicketForm) error {
+//			// ...
+//		},
+//	))
+func FormHandler[T any](handler func(req *ViewSubmissionRequest, form T) error) ViewSubmissionHandler {
+	return func(req *ViewSubmissionRequest) error {
+		var form T
+		err := req.Bind(&form)
+		if fields, ok := ErrorFields(err); ok {
+			req.AckWithAction(ViewResponseAction{
+				ResponseAction: ViewResponseErrors,
+				Errors:         fields,
+			})
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return handler(req, form)
+	}
+}
+
+// Validates a bound struct's "validate" tags, returning error
+// messages by block ID. Whether a value was entered is read from the
+// state, so an entered 0 is checked against min and max.
+//
+// Rules are separated by commas:
+//
+//   - required: a value must be entered or selected
+//   - min=N, max=N: the minimum and maximum length of strings,
+//     number of values in slices, or value of numbers
+//   - pattern=REGEXP: strings must match the regular expression.
+//     Must be the last rule, as the rest of the tag is the pattern.
+func validateStruct(rv reflect.Value, state map[string]map[string]slack.BlockAction) (map[string]string, error) {
+	rt := rv.Type()
+	fields := make(map[string]string)
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+		blockID, actionID, ok, err := parseBindTag(field)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if _, ok := fields[blockID]; ok {
+			continue
+		}
+		action, ok := state[blockID][actionID]
+		entered := ok && hasValue(action)
+		message, err := validateField(rv.Field(i), entered, tag)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid validate tag on %v: %w", field.Name, err)
+		}
+		if message != "" {
+			fields[blockID] = message
+		}
+	}
+	return fields, nil
+}
+
+func validateField(value reflect.Value, entered bool, tag string) (string, error) {
+	for tag != "" {
+		var rule string
+		if strings.HasPrefix(tag, "pattern=") {
+			rule, tag = tag, ""
+		} else {
+			rule, tag, _ = strings.Cut(tag, ",")
+		}
+		name, arg, _ := strings.Cut(rule, "=")
+
+		if name == "required" {
+			if !entered {
+				return "This field is required.", nil
+			}
+			continue
+		}
+
+		// Other rules only apply to values that were entered
+		if !entered {
+			continue
+		}
+		if value.Kind() == reflect.Pointer {
+			value = value.Elem()
+		}
+
+		switch name {
+		case "min", "max":
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return "", fmt.Errorf("%v must be a number", name)
+			}
+			if message := checkLimit(value, name, limit); message != "" {
+				return message, nil
+			}
+		case "pattern":
+			pattern, err := regexp.Compile(arg)
+			if err != nil {
+				return "", err
+			}
+			if value.Kind() == reflect.String && !pattern.MatchString(value.String()) {
+				return "Is not in the expected format.", nil
+			}
+		default:
+			return "", fmt.Errorf("Unknown rule %q", name)
+		}
+	}
+	return "", nil
+}
+
+func checkLimit(value reflect.Value, name string, limit float64) string {
+	var n float64
+	var unit string
+	switch value.Kind() {
+	case reflect.String:
+		n, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice:
+		n = float64(value.Len())
+		if name == "min" && n < limit {
+			return fmt.Sprintf("Select at least %v.", limit)
+		}
+		if name == "max" && n > limit {
+			return fmt.Sprintf("Select at most %v.", limit)
+		}
+		return ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = value.Float()
+	default:
+		return ""
+	}
+	if name == "min" && n < limit {
+		return fmt.Sprintf("Must be at least %v%v.", limit, unit)
+	}
+	if name == "max" && n > limit {
+		return fmt.Sprintf("Must be at most %v%v.", limit, unit)
+	}
+	return ""
+}
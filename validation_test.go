@@ -0,0 +1,217 @@
+package slap_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+func TestViewValidator(t *testing.T) {
+	t.Parallel()
+
+	called := make(chan bool, 1)
+	app, router := createTestApp()
+	app.RegisterViewSubmission("form", func(req *slap.ViewSubmissionRequest) error {
+		called <- true
+		req.Ack()
+		return nil
+	})
+	app.RegisterViewValidator("form", slap.Validator{
+		"name/input":  {slap.Required(), slap.Length(3, 10)},
+		"age/input":   {slap.Range(18, 130)},
+		"code/input":  {slap.Match(regexp.MustCompile(`^[A-Z]{3}$`), "Enter a three letter code.")},
+		"notes/input": {slap.Required()},
+		"tags/select": {slap.Count(1, 2)},
+		"slug/input": {func(values []string) error {
+			if len(values) > 0 && strings.Contains(values[0], " ") {
+				return errors.New("No spaces allowed.")
+			}
+			return nil
+		}},
+	})
+	tester := slaptest.New(router, "signing-secret")
+
+	options := []slack.OptionBlockObject{{Value: "a"}, {Value: "b"}, {Value: "c"}}
+	res := tester.ViewSubmission(slaptest.ViewSubmission{
+		View: slack.View{CallbackID: "form"},
+		Values: map[string]map[string]slack.BlockAction{
+			"name": {"input": {Type: "plain_text_input", Value: "Al"}},
+			"age":  {"input": {Type: "number_input", Value: "12"}},
+			"code": {"input": {Type: "plain_text_input", Value: "abc"}},
+			"tags": {"select": {Type: "multi_static_select", SelectedOptions: options}},
+			"slug": {"input": {Type: "plain_text_input", Value: "my slug"}},
+		},
+	})
+
+	var action slap.ViewResponseAction
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	want := map[string]string{
+		"name":  "Must be at least 3 characters.",
+		"age":   "Must be between 18 and 130.",
+		"code":  "Enter a three letter code.",
+		"notes": "This field is required.",
+		"tags":  "Select at most 2.",
+		"slug":  "No spaces allowed.",
+	}
+	if action.ResponseAction != slap.ViewResponseErrors || !reflect.DeepEqual(action.Errors, want) {
+		t.Errorf("Unexpected response, got: %+v, want errors: %v", action, want)
+	}
+
+	select {
+	case <-called:
+		t.Errorf("Handler was called for an invalid submission")
+	default:
+	}
+
+	res = tester.ViewSubmission(slaptest.ViewSubmission{
+		View: slack.View{CallbackID: "form"},
+		Values: map[string]map[string]slack.BlockAction{
+			"name":  {"input": {Type: "plain_text_input", Value: "Alice"}},
+			"notes": {"input": {Type: "plain_text_input", Value: "Hi"}},
+		},
+	})
+	if res.StatusCode != http.StatusOK || !<-called {
+		t.Errorf("Expected the handler to be called for a valid submission")
+	}
+}
+
+type validatedForm struct {
+	Name    string   `slap:"name/input" validate:"required,min=3,max=10"`
+	Age     int      `slap:"age/input" validate:"min=18,max=130"`
+	Code    string   `slap:"code/input" validate:"pattern=^[A-Z]{3,5}$"`
+	Tags    []string `slap:"tags/select" validate:"required,max=2"`
+	Website *string  `slap:"website/input" validate:"min=10"`
+}
+
+func TestFormHandler(t *testing.T) {
+	t.Parallel()
+
+	forms := make(chan validatedForm, 1)
+	app, router := createTestApp()
+	app.RegisterViewSubmission("form", slap.FormHandler(func(req *slap.ViewSubmissionRequest, form validatedForm) error {
+		forms <- form
+		req.Ack()
+		return nil
+	}))
+	tester := slaptest.New(router, "signing-secret")
+
+	res := tester.ViewSubmission(slaptest.ViewSubmission{
+		View: slack.View{CallbackID: "form"},
+		Values: map[string]map[string]slack.BlockAction{
+			"name": {"input": {Type: "plain_text_input", Value: "Alice Wonderland"}},
+			"age":  {"input": {Type: "number_input", Value: "twelve"}},
+			"code": {"input": {Type: "plain_text_input", Value: "ab"}},
+		},
+	})
+
+	var action slap.ViewResponseAction
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	want := map[string]string{
+		"name": "Must be at most 10 characters.",
+		"age":  "Enter a whole number.",
+		"code": "Is not in the expected format.",
+		"tags": "This field is required.",
+	}
+	if !reflect.DeepEqual(action.Errors, want) {
+		t.Errorf("Unexpected errors, got: %v, want: %v", action.Errors, want)
+	}
+
+	tester.ViewSubmission(slaptest.ViewSubmission{
+		View: slack.View{CallbackID: "form"},
+		Values: map[string]map[string]slack.BlockAction{
+			"name": {"input": {Type: "plain_text_input", Value: "Alice"}},
+			"age":  {"input": {Type: "number_input", Value: "30"}},
+			"tags": {"select": {Type: "multi_static_select", SelectedOptions: []slack.OptionBlockObject{{Value: "a"}}}},
+		},
+	})
+
+	form := <-forms
+	if form.Name != "Alice" || form.Age != 30 || len(form.Tags) != 1 || form.Website != nil {
+		t.Errorf("Unexpected form: %+v", form)
+	}
+}
+
+type zeroForm struct {
+	Count  int     `slap:"count/input" validate:"required,min=0"`
+	Rating int     `slap:"rating/input" validate:"min=1,max=5"`
+	Offset float64 `slap:"offset/input" validate:"max=-1"`
+}
+
+func TestFormHandlerZeroValues(t *testing.T) {
+	t.Parallel()
+
+	app, router := createTestApp()
+	app.RegisterViewSubmission("form", slap.FormHandler(func(req *slap.ViewSubmissionRequest, form zeroForm) error {
+		req.Ack()
+		return nil
+	}))
+	tester := slaptest.New(router, "signing-secret")
+
+	res := tester.ViewSubmission(slaptest.ViewSubmission{
+		View: slack.View{CallbackID: "form"},
+		Values: map[string]map[string]slack.BlockAction{
+			"count":  {"input": {Type: "number_input", Value: "0"}},
+			"rating": {"input": {Type: "number_input", Value: "0"}},
+			"offset": {"input": {Type: "number_input", Value: "0"}},
+		},
+	})
+
+	var action slap.ViewResponseAction
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	want := map[string]string{
+		"rating": "Must be at least 1.",
+		"offset": "Must be at most -1.",
+	}
+	if !reflect.DeepEqual(action.Errors, want) {
+		t.Errorf("Unexpected errors, got: %v, want: %v", action.Errors, want)
+	}
+}
+
+func TestViewValidatorOrder(t *testing.T) {
+	t.Parallel()
+
+	app, router := createTestApp()
+	app.RegisterViewSubmission("form", func(req *slap.ViewSubmissionRequest) error {
+		req.Ack()
+		return nil
+	})
+	app.RegisterViewValidator("form", slap.Validator{
+		"date/c": {fail("Pick a later date.")},
+		"date/a": {fail("Pick a date.")},
+		"date/b": {fail("Pick a weekday.")},
+	})
+	tester := slaptest.New(router, "signing-secret")
+
+	for i := 0; i < 20; i++ {
+		res := tester.ViewSubmission(slaptest.ViewSubmission{View: slack.View{CallbackID: "form"}})
+		var action slap.ViewResponseAction
+		if err := res.JSON(&action); err != nil {
+			t.Fatalf("Could not decode response: %v", err.Error())
+		}
+		if action.Errors["date"] != "Pick a date." {
+			t.Fatalf("Unexpected errors: %v", action.Errors)
+		}
+	}
+}
+
+func fail(message string) slap.Rule {
+	return func(values []string) error {
+		return errors.New(message)
+	}
+}
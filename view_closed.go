@@ -0,0 +1,92 @@
+package slap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/slack-go/slack"
+)
+
+// The payload of a Slack view_closed request, sent when a user closes
+// a modal with notify_on_close set
+type ViewClosedPayload struct {
+	interactionPayload
+	View slack.View `json:"view"`
+	// Whether the whole modal stack was closed
+	IsCleared bool `json:"is_cleared"`
+}
+
+// A Slack view closed request
+type ViewClosedRequest struct {
+	baseRequest
+	Payload ViewClosedPayload
+}
+
+// A function to handle a view closed request
+type ViewClosedHandler func(req *ViewClosedRequest) error
+
+// Registers a view closed handler.
+//
+// Panics if the callbackID has already been registered.
+func (app *Application) RegisterViewClosed(callbackID string, handler ViewClosedHandler) {
+	_, ok := app.viewClosed[callbackID]
+	if ok {
+		panic(fmt.Sprintf("View Closed Callback ID %v has already been registered", callbackID))
+	}
+	app.viewClosed[callbackID] = handler
+	app.logger.Info("Registered View Closed Handler", "callbackID", callbackID)
+}
+
+func (app *Application) handleViewClosed(w http.ResponseWriter, blob []byte) {
+	var payload ViewClosedPayload
+	err := json.Unmarshal(blob, &payload)
+	if err != nil {
+		app.logger.Error("Could not parse ViewClosedPayload", "error", err.Error())
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	handler, ok := app.viewClosed[payload.View.CallbackID]
+	if !ok {
+		// Return 200 for views without a handler
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	ackChan := make(chan []byte)
+	errChan := make(chan error)
+
+	go func() {
+		req := &ViewClosedRequest{
+			Payload: payload,
+			baseRequest: baseRequest{
+				errChannel: errChan,
+				ackChannel: ackChan,
+				ackCalled:  false,
+				writer:     w,
+				Logger:     app.logger,
+				app:        app,
+				authorize:  payload.authorizeContext(),
+				context:    payload.requestContext(),
+			},
+		}
+		err := handler(req)
+		if err == nil {
+			return
+		}
+		app.logger.Error("A view closed handler failed", "callbackID", payload.View.CallbackID, "code", ErrorCode(err), "error", err.Error())
+		app.handleError(req, ViewClosedRequestKind, &req.Payload, err)
+	}()
+
+	select {
+	case <-ackChan:
+		w.Write(nil)
+	case err := <-errChan:
+		if err == nil {
+			return
+		}
+		http.Error(w, "An error occurred", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,83 @@
+package slap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+func TestViewClosed(t *testing.T) {
+	t.Parallel()
+
+	payloads := make(chan slap.ViewClosedPayload, 1)
+	app, router := createTestApp()
+	app.RegisterViewClosed("wizard", func(req *slap.ViewClosedRequest) error {
+		req.Ack()
+		payloads <- req.Payload
+		return nil
+	})
+
+	tester := slaptest.New(router, "signing-secret")
+	res := tester.ViewClosed(slaptest.ViewClosed{
+		View:      slack.View{CallbackID: "wizard"},
+		IsCleared: true,
+	})
+
+	statusGot, statusWant := res.StatusCode, 200
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	payload := <-payloads
+	if !payload.IsCleared {
+		t.Errorf("Expected is_cleared to be true")
+	}
+
+	userGot, userWant := payload.User.ID, slaptest.DefaultUserID
+	if userGot != userWant {
+		t.Errorf("Unexpected user, got: %v, want: %v", userGot, userWant)
+	}
+}
+
+func TestViewClosedNoHandler(t *testing.T) {
+	t.Parallel()
+
+	_, router := createTestApp()
+	tester := slaptest.New(router, "signing-secret")
+	res := tester.ViewClosed(slaptest.ViewClosed{View: slack.View{CallbackID: "unknown"}})
+
+	statusGot, statusWant := res.StatusCode, 200
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+}
+
+func TestViewClosedHandlerError(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+	app.RegisterViewClosed("wizard", func(req *slap.ViewClosedRequest) error {
+		return slap.UserError("Your draft could not be discarded")
+	})
+
+	res := tester.ViewClosed(slaptest.ViewClosed{View: slack.View{CallbackID: "wizard"}})
+
+	statusGot, statusWant := res.StatusCode, 200
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	call, err := server.WaitForCall("chat.postMessage", time.Second)
+	if err != nil {
+		t.Fatalf("Expected a direct message: %v", err.Error())
+	}
+
+	textGot, textWant := call.Params.Get("text"), "Your draft could not be discarded"
+	if textGot != textWant {
+		t.Errorf("Unexpected message, got: %v, want: %v", textGot, textWant)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/jacob-ian/slap/blocks"
 	"github.com/slack-go/slack"
 )
 
@@ -49,12 +50,50 @@ type ViewResponseAction struct {
 	Errors map[string]string `json:"errors,omitempty"`
 }
 
+// Encodes the action with only the view fields Slack accepts in
+// a response action.
+func (a ViewResponseAction) MarshalJSON() ([]byte, error) {
+	var view *slack.ModalViewRequest
+	if a.View != nil {
+		request := modalViewRequest(a.View)
+		view = &request
+	}
+	return json.Marshal(struct {
+		ResponseAction ViewResponseActionType  `json:"response_action"`
+		View           *slack.ModalViewRequest `json:"view,omitempty"`
+		Errors         map[string]string       `json:"errors,omitempty"`
+	}{a.ResponseAction, view, a.Errors})
+}
+
+// The fields of a view that can be sent to Slack.
+func modalViewRequest(view *slack.View) slack.ModalViewRequest {
+	return slack.ModalViewRequest{
+		Type:            view.Type,
+		Title:           view.Title,
+		Blocks:          view.Blocks,
+		Close:           view.Close,
+		Submit:          view.Submit,
+		PrivateMetadata: view.PrivateMetadata,
+		CallbackID:      view.CallbackID,
+		ClearOnClose:    view.ClearOnClose,
+		NotifyOnClose:   view.NotifyOnClose,
+		ExternalID:      view.ExternalID,
+	}
+}
+
 // Immediately respond to Slack with a view response action
 func (req *ViewSubmissionRequest) AckWithAction(action ViewResponseAction) {
 	if req.ackCalled {
 		return
 	}
 	req.ackCalled = true
+	if action.View != nil && req.app.blockLimits != IgnoreBlockLimits {
+		err := req.checkBlockLimits("view response action", blocks.ValidateView(modalViewRequest(action.View)))
+		if err != nil {
+			req.errChannel <- err
+			return
+		}
+	}
 	bytes, err := json.Marshal(action)
 	if err != nil {
 		req.Logger.Error("Could not encode view response action", "error", err.Error())
@@ -76,19 +115,17 @@ func (app *Application) handleViewSubmission(w http.ResponseWriter, blob []byte)
 		return
 	}
 
-	botToken, err := app.botToken(payload.Team.ID)
-	if err != nil {
-		app.logger.Error("Could not get bot token", "teamID", payload.Team.ID, "error", err.Error())
-		http.Error(w, "Could not get bot token", http.StatusInternalServerError)
-		return
-	}
-
+	authorize := payload.authorizeContext()
 	handler, ok := app.viewSubmissions[payload.View.CallbackID]
 	if !ok {
 		http.Error(w, "Invalid callback ID", http.StatusInternalServerError)
 		return
 	}
 
+	if app.rejectInvalidSubmission(w, payload) {
+		return
+	}
+
 	ackChan := make(chan []byte)
 	errChan := make(chan error)
 
@@ -100,20 +137,18 @@ func (app *Application) handleViewSubmission(w http.ResponseWriter, blob []byte)
 				ackChannel: ackChan,
 				ackCalled:  false,
 				writer:     w,
-				Client:     slack.New(botToken),
 				Logger:     app.logger,
+				app:        app,
+				authorize:  authorize,
+				context:    payload.requestContext(),
 			},
 		}
 		err := handler(req)
 		if err == nil {
 			return
 		}
-		app.logger.Error("A view submission handler failed", "callbackID", req.Payload.View.CallbackID, "error", err.Error())
-		_, msgerr := req.Client.PostEphemeral(req.Payload.User.ID, req.Payload.User.ID, slack.MsgOptionText(app.errorMessage, false))
-		if msgerr != nil {
-			app.logger.Error("Unable to send error message to user", "user", req.Payload.User.ID, "error", msgerr.Error())
-		}
-		errChan <- err
+		app.logger.Error("A view submission handler failed", "callbackID", req.Payload.View.CallbackID, "code", ErrorCode(err), "error", err.Error())
+		app.handleError(req, ViewSubmissionRequestKind, &req.Payload, err)
 	}()
 
 	select {
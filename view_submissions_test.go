@@ -2,6 +2,7 @@ package slap_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/jacob-ian/slap"
+	"github.com/slack-go/slack"
 )
 
 func TestViewSubmissionNoSignatureHeader(t *testing.T) {
@@ -175,19 +177,29 @@ func TestViewSubmissionHandlerError(t *testing.T) {
 	router.ServeHTTP(w, r)
 	res := w.Result()
 
-	statusGot, statusWant := res.StatusCode, http.StatusInternalServerError
+	statusGot, statusWant := res.StatusCode, http.StatusOK
 	if statusGot != statusWant {
 		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
 	}
 
-	bodyGot, err := io.ReadAll(res.Body)
-	if err != nil {
-		t.Errorf("Could not read res body: %v", err.Error())
+	var action slap.ViewResponseAction
+	if err = json.NewDecoder(res.Body).Decode(&action); err != nil {
+		t.Fatalf("Could not decode response action: %v", err.Error())
 	}
 
-	textGot, textWant := string(bodyGot), "An error occurred\n"
+	actionGot, actionWant := action.ResponseAction, slap.ViewResponseUpdate
+	if actionGot != actionWant {
+		t.Errorf("Unexpected response action, got: %v, want: %v", actionGot, actionWant)
+	}
+
+	section, ok := action.View.Blocks.BlockSet[0].(*slack.SectionBlock)
+	if !ok {
+		t.Fatalf("Unexpected error view block: %v", action.View.Blocks.BlockSet[0])
+	}
+
+	textGot, textWant := section.Text.Text, "An error occurred"
 	if textGot != textWant {
-		t.Errorf("Unexpected body, got: %v, want: %v", textGot, textWant)
+		t.Errorf("Unexpected error message, got: %v, want: %v", textGot, textWant)
 	}
 }
 
@@ -0,0 +1,331 @@
+package slap
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jacob-ian/slap/blocks"
+	"github.com/slack-go/slack"
+)
+
+// Special step names returned by a WizardStep's Submit function
+const (
+	// Returns to the previous step
+	WizardBack = "<back>"
+	// Closes the wizard's modal stack
+	WizardFinish = "<finish>"
+)
+
+// A step of a Wizard, showing a modal view
+type WizardStep[T any] struct {
+	// Required. Identifies the step for branching.
+	Name string
+	// Required. Creates the step's view from the wizard's state.
+	//
+	// The wizard sets the view's CallbackID, PrivateMetadata and
+	// NotifyOnClose, and defaults its Type to "modal".
+	Render func(req Request, state T) (slack.ModalViewRequest, error)
+	// Optional. Handles the step's submission, e.g. by binding its
+	// inputs into the state, and returns the name of the next step.
+	//
+	// Return "" to go to the next step in order, or finish after
+	// the last step. Return WizardBack or WizardFinish to go back or
+	// finish. Return FieldErrors to show messages below the inputs.
+	Submit func(req *ViewSubmissionRequest, state *T) (string, error)
+	// Push the step onto the modal stack instead of updating the
+	// current view, so closing it or going back returns to the
+	// previous view.
+	//
+	// Slack allows up to 3 views in a modal stack.
+	Push bool
+	// Don't add a Back button to the step.
+	NoBack bool
+}
+
+// A multi-step modal, which carries its state of type T between
+// steps in the view's private_metadata. See EncodeMetadata.
+//
+//	wizard := slap.NewWizard[Ticket]("ticket-wizard")
+//	wizard.Step(slap.WizardStep[Ticket]{Name: "details", Render: ..., Submit: ...})
+//	wizard.Step(slap.WizardStep[Ticket]{Name: "review", Render: ..., Submit: ...})
+//	slap.RegisterWizard(app, wizard)
+//
+//	app.RegisterCommand("/ticket", func(req *slap.CommandRequest) error {
+//		req.Ack()
+//		return wizard.Open(req, Ticket{})
+//	})
+type Wizard[T any] struct {
+	callbackID string
+	backText   string
+	steps      []WizardStep[T]
+	onClose    func(req *ViewClosedRequest, state T) error
+}
+
+// The state of a wizard stored in a view's private_metadata
+type wizardMetadata[T any] struct {
+	Step    string   `json:"step"`
+	History []string `json:"history,omitempty"`
+	// The stored private_metadata of the views under a pushed step
+	Parents []string `json:"parents,omitempty"`
+	State   T        `json:"state"`
+}
+
+// Creates a Wizard whose views use the callbackID.
+func NewWizard[T any](callbackID string) *Wizard[T] {
+	return &Wizard[T]{callbackID: callbackID, backText: "Back"}
+}
+
+// Adds a step to the wizard. The first step is shown when it opens.
+//
+// Panics if the step has no name or Render function, or its name
+// has already been added.
+func (w *Wizard[T]) Step(step WizardStep[T]) {
+	if step.Name == "" || step.Name == WizardBack || step.Name == WizardFinish {
+		panic(fmt.Sprintf("Invalid Wizard step name %q", step.Name))
+	}
+	if step.Render == nil {
+		panic(fmt.Sprintf("Wizard step %v has no Render function", step.Name))
+	}
+	if _, ok := w.find(step.Name); ok {
+		panic(fmt.Sprintf("Wizard step %v has already been added", step.Name))
+	}
+	w.steps = append(w.steps, step)
+}
+
+// Sets a function called with the final state when the user closes
+// the wizard before finishing.
+func (w *Wizard[T]) OnClose(handler func(req *ViewClosedRequest, state T) error) {
+	w.onClose = handler
+}
+
+// Sets the text of the Back button. Defaults to "Back".
+func (w *Wizard[T]) BackText(text string) {
+	w.backText = text
+}
+
+// The action ID of the wizard's Back button, which can be used in a
+// step's own blocks.
+func (w *Wizard[T]) BackActionID() string {
+	return w.callbackID + ".back"
+}
+
+// Registers the wizard's view submission, Back button and view
+// closed handlers with the app.
+//
+// Panics if the wizard has no steps, or its handlers have already
+// been registered.
+func RegisterWizard[T any](app *Application, wizard *Wizard[T]) {
+	if len(wizard.steps) == 0 {
+		panic(fmt.Sprintf("Wizard %v has no steps", wizard.callbackID))
+	}
+	app.RegisterViewSubmission(wizard.callbackID, wizard.handleSubmission)
+	app.RegisterBlockAction(wizard.BackActionID(), wizard.handleBack)
+	app.RegisterViewClosed(wizard.callbackID, wizard.handleClosed)
+}
+
+// Opens the wizard at its first step with an initial state, using
+// the request's trigger ID.
+func (w *Wizard[T]) Open(req Request, state T) error {
+	if len(w.steps) == 0 {
+		return fmt.Errorf("Wizard %v has no steps", w.callbackID)
+	}
+	triggerID := req.RequestContext().TriggerID
+	if triggerID == "" {
+		return errors.New("Wizards can only be opened from requests with a trigger ID")
+	}
+	view, err := w.render(req, wizardMetadata[T]{Step: w.steps[0].Name, State: state})
+	if err != nil {
+		return err
+	}
+	_, err = req.Client().OpenView(triggerID, view)
+	return err
+}
+
+func (w *Wizard[T]) handleSubmission(req *ViewSubmissionRequest) error {
+	var metadata wizardMetadata[T]
+	if err := req.DecodeMetadata(&metadata); err != nil {
+		return err
+	}
+	index, ok := w.find(metadata.Step)
+	if !ok {
+		return fmt.Errorf("Unknown Wizard step %v", metadata.Step)
+	}
+	step := w.steps[index]
+
+	next := ""
+	if step.Submit != nil {
+		var err error
+		next, err = step.Submit(req, &metadata.State)
+		if err != nil {
+			return err
+		}
+		if req.ackCalled {
+			return nil
+		}
+	}
+
+	if next == "" {
+		if index == len(w.steps)-1 {
+			next = WizardFinish
+		} else {
+			next = w.steps[index+1].Name
+		}
+	}
+
+	if next == WizardFinish {
+		req.AckWithAction(ViewResponseAction{ResponseAction: ViewResponseClear})
+		return w.deleteMetadata(req.app, req.Payload.View.PrivateMetadata, metadata.Parents)
+	}
+
+	action := ViewResponseUpdate
+	if next == WizardBack {
+		if len(metadata.History) == 0 {
+			return errors.New("The first Wizard step has no previous step")
+		}
+		// Closing a pushed step returns to the previous step's view
+		if step.Push {
+			req.Ack()
+			return req.app.deleteMetadata(req.Payload.View.PrivateMetadata)
+		}
+		next = metadata.History[len(metadata.History)-1]
+		metadata.History = metadata.History[:len(metadata.History)-1]
+	} else {
+		nextIndex, ok := w.find(next)
+		if !ok {
+			return fmt.Errorf("Unknown Wizard step %v", next)
+		}
+		metadata.History = append(metadata.History, metadata.Step)
+		if w.steps[nextIndex].Push {
+			action = ViewResponsePush
+			if private := req.Payload.View.PrivateMetadata; isStoredMetadata(private) {
+				metadata.Parents = append(metadata.Parents, private)
+			}
+		}
+	}
+	metadata.Step = next
+
+	view, err := w.render(req, metadata)
+	if err != nil {
+		return err
+	}
+	req.AckWithAction(ViewResponseAction{ResponseAction: action, View: blocks.ResponseView(view)})
+
+	// A pushed view returns to the submitted view when closed
+	if action == ViewResponsePush {
+		return nil
+	}
+	return req.app.deleteMetadata(req.Payload.View.PrivateMetadata)
+}
+
+func (w *Wizard[T]) handleBack(req *BlockActionRequest) error {
+	req.Ack()
+	if req.Payload.View == nil {
+		return errors.New("The Wizard Back button must be in a modal")
+	}
+
+	var metadata wizardMetadata[T]
+	if err := req.DecodeMetadata(&metadata); err != nil {
+		return err
+	}
+	if len(metadata.History) == 0 {
+		return nil
+	}
+	metadata.Step = metadata.History[len(metadata.History)-1]
+	metadata.History = metadata.History[:len(metadata.History)-1]
+
+	view, err := w.render(req, metadata)
+	if err != nil {
+		return err
+	}
+	_, err = req.Client().UpdateView(view, "", req.Payload.View.Hash, req.Payload.View.ID)
+	if err != nil {
+		return err
+	}
+	return req.app.deleteMetadata(req.Payload.View.PrivateMetadata)
+}
+
+func (w *Wizard[T]) handleClosed(req *ViewClosedRequest) error {
+	req.Ack()
+
+	var metadata wizardMetadata[T]
+	if err := req.DecodeMetadata(&metadata); err != nil {
+		return err
+	}
+	parents := metadata.Parents
+	if !req.Payload.IsCleared {
+		parents = nil
+	}
+	if err := w.deleteMetadata(req.app, req.Payload.View.PrivateMetadata, parents); err != nil {
+		return err
+	}
+
+	// A pushed step was closed, returning to the previous view
+	view := req.Payload.View
+	if !req.Payload.IsCleared && view.RootViewID != "" && view.ID != view.RootViewID {
+		return nil
+	}
+	if w.onClose == nil {
+		return nil
+	}
+	return w.onClose(req, metadata.State)
+}
+
+// Renders a step's view with the wizard's metadata.
+func (w *Wizard[T]) render(req Request, metadata wizardMetadata[T]) (slack.ModalViewRequest, error) {
+	index, ok := w.find(metadata.Step)
+	if !ok {
+		return slack.ModalViewRequest{}, fmt.Errorf("Unknown Wizard step %v", metadata.Step)
+	}
+	step := w.steps[index]
+
+	view, err := step.Render(req, metadata.State)
+	if err != nil {
+		return slack.ModalViewRequest{}, err
+	}
+	base := baseOf(req)
+	if base == nil {
+		return slack.ModalViewRequest{}, errors.New("Wizards can only be used with requests from an Application")
+	}
+	privateMetadata, err := base.app.encodeMetadata(metadata)
+	if err != nil {
+		return slack.ModalViewRequest{}, err
+	}
+
+	if view.Type == "" {
+		view.Type = slack.VTModal
+	}
+	view.CallbackID = w.callbackID
+	view.PrivateMetadata = privateMetadata
+	view.NotifyOnClose = true
+
+	if len(metadata.History) > 0 && !step.NoBack && !step.Push {
+		back := slack.NewButtonBlockElement(w.BackActionID(), "", slack.NewTextBlockObject(slack.PlainTextType, w.backText, false, false))
+		blocks := make([]slack.Block, 0, len(view.Blocks.BlockSet)+1)
+		blocks = append(blocks, view.Blocks.BlockSet...)
+		view.Blocks = slack.Blocks{BlockSet: append(blocks, slack.NewActionBlock(w.callbackID+".navigation", back))}
+	}
+	return view, nil
+}
+
+// Deletes a view's metadata and the metadata of the views under it
+// from the Config.MetadataStore.
+func (w *Wizard[T]) deleteMetadata(app *Application, metadata string, parents []string) error {
+	if err := app.deleteMetadata(metadata); err != nil {
+		return err
+	}
+	for _, parent := range parents {
+		if err := app.deleteMetadata(parent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Wizard[T]) find(name string) (int, bool) {
+	for i, step := range w.steps {
+		if step.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
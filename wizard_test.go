@@ -0,0 +1,382 @@
+package slap_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jacob-ian/slap"
+	"github.com/jacob-ian/slap/slaptest"
+	"github.com/slack-go/slack"
+)
+
+type ticket struct {
+	Title  string `json:"title"`
+	Urgent bool   `json:"urgent"`
+}
+
+func renderStep(title string) func(req slap.Request, state ticket) (slack.ModalViewRequest, error) {
+	return func(req slap.Request, state ticket) (slack.ModalViewRequest, error) {
+		return slack.ModalViewRequest{
+			Title: slack.NewTextBlockObject(slack.PlainTextType, title, false, false),
+			Blocks: slack.Blocks{BlockSet: []slack.Block{
+				slack.NewSectionBlock(slack.NewTextBlockObject(slack.PlainTextType, state.Title, false, false), nil, nil),
+			}},
+		}, nil
+	}
+}
+
+func createTicketWizard() *slap.Wizard[ticket] {
+	wizard := slap.NewWizard[ticket]("ticket-wizard")
+	wizard.Step(slap.WizardStep[ticket]{
+		Name:   "details",
+		Render: renderStep("Details"),
+		Submit: func(req *slap.ViewSubmissionRequest, state *ticket) (string, error) {
+			state.Title = "Printer on fire"
+			if state.Urgent {
+				return "escalate", nil
+			}
+			return "", nil
+		},
+	})
+	wizard.Step(slap.WizardStep[ticket]{
+		Name:   "review",
+		Render: renderStep("Review"),
+		Submit: func(req *slap.ViewSubmissionRequest, state *ticket) (string, error) {
+			return slap.WizardFinish, nil
+		},
+	})
+	wizard.Step(slap.WizardStep[ticket]{
+		Name:   "escalate",
+		Render: renderStep("Escalate"),
+		Submit: func(req *slap.ViewSubmissionRequest, state *ticket) (string, error) {
+			return slap.WizardBack, nil
+		},
+		Push: true,
+	})
+	return wizard
+}
+
+// Opens the wizard from a command, returning the opened view.
+func openTicketWizard(t *testing.T, state ticket) (*slap.Application, *slaptest.Tester, *slaptest.Server, slack.View) {
+	t.Helper()
+	app, tester, server := createConversationTestApp()
+	wizard := createTicketWizard()
+	slap.RegisterWizard(app, wizard)
+	app.RegisterCommand("/ticket", func(req *slap.CommandRequest) error {
+		req.Ack()
+		return wizard.Open(req, state)
+	})
+	tester.Command(slaptest.Command{Command: "/ticket"})
+
+	call, err := server.WaitForCall("views.open", time.Second)
+	if err != nil {
+		t.Fatalf("Expected a modal to be opened: %v", err.Error())
+	}
+	var view slack.View
+	if err := json.Unmarshal([]byte(call.Params.Get("view")), &view); err != nil {
+		t.Fatalf("Could not decode view: %v", err.Error())
+	}
+	return app, tester, server, view
+}
+
+func TestWizardOpen(t *testing.T) {
+	t.Parallel()
+
+	_, _, server, view := openTicketWizard(t, ticket{})
+	defer server.Close()
+
+	callbackGot, callbackWant := view.CallbackID, "ticket-wizard"
+	if callbackGot != callbackWant {
+		t.Errorf("Unexpected callback ID, got: %v, want: %v", callbackGot, callbackWant)
+	}
+	if !view.NotifyOnClose {
+		t.Errorf("Expected notify_on_close to be set")
+	}
+	if view.PrivateMetadata == "" {
+		t.Errorf("Expected private metadata to be set")
+	}
+}
+
+func TestWizardNext(t *testing.T) {
+	t.Parallel()
+
+	_, tester, server, view := openTicketWizard(t, ticket{})
+	defer server.Close()
+
+	res := tester.ViewSubmission(slaptest.ViewSubmission{View: view})
+	var action struct {
+		ResponseAction string     `json:"response_action"`
+		View           slack.View `json:"view"`
+	}
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	actionGot, actionWant := action.ResponseAction, "update"
+	if actionGot != actionWant {
+		t.Errorf("Unexpected response action, got: %v, want: %v", actionGot, actionWant)
+	}
+
+	titleGot, titleWant := action.View.Title.Text, "Review"
+	if titleGot != titleWant {
+		t.Errorf("Unexpected step, got: %v, want: %v", titleGot, titleWant)
+	}
+
+	blocks := action.View.Blocks.BlockSet
+	section, ok := blocks[0].(*slack.SectionBlock)
+	if !ok || section.Text.Text != "Printer on fire" {
+		t.Errorf("Expected the state to be carried to the next step")
+	}
+	if _, ok := blocks[len(blocks)-1].(*slack.ActionBlock); !ok {
+		t.Errorf("Expected a Back button")
+	}
+
+	res = tester.ViewSubmission(slaptest.ViewSubmission{View: action.View})
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	actionGot, actionWant = action.ResponseAction, "clear"
+	if actionGot != actionWant {
+		t.Errorf("Unexpected response action, got: %v, want: %v", actionGot, actionWant)
+	}
+}
+
+func TestWizardBranch(t *testing.T) {
+	t.Parallel()
+
+	_, tester, server, view := openTicketWizard(t, ticket{Urgent: true})
+	defer server.Close()
+
+	res := tester.ViewSubmission(slaptest.ViewSubmission{View: view})
+	var action struct {
+		ResponseAction string     `json:"response_action"`
+		View           slack.View `json:"view"`
+	}
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	actionGot, actionWant := action.ResponseAction, "push"
+	if actionGot != actionWant {
+		t.Errorf("Unexpected response action, got: %v, want: %v", actionGot, actionWant)
+	}
+
+	titleGot, titleWant := action.View.Title.Text, "Escalate"
+	if titleGot != titleWant {
+		t.Errorf("Unexpected step, got: %v, want: %v", titleGot, titleWant)
+	}
+}
+
+func TestWizardBack(t *testing.T) {
+	t.Parallel()
+
+	_, tester, server, view := openTicketWizard(t, ticket{})
+	defer server.Close()
+
+	res := tester.ViewSubmission(slaptest.ViewSubmission{View: view})
+	var action struct {
+		View slack.View `json:"view"`
+	}
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	review := action.View
+	review.ID = "V0000002"
+	tester.BlockAction(slaptest.BlockAction{
+		Action: slack.BlockAction{ActionID: "ticket-wizard.back", Type: "button"},
+		View:   &review,
+	})
+
+	call, err := server.WaitForCall("views.update", time.Second)
+	if err != nil {
+		t.Fatalf("Expected the modal to be updated: %v", err.Error())
+	}
+
+	viewIDGot, viewIDWant := call.Params.Get("view_id"), "V0000002"
+	if viewIDGot != viewIDWant {
+		t.Errorf("Unexpected view ID, got: %v, want: %v", viewIDGot, viewIDWant)
+	}
+
+	var updated slack.View
+	if err := json.Unmarshal([]byte(call.Params.Get("view")), &updated); err != nil {
+		t.Fatalf("Could not decode view: %v", err.Error())
+	}
+	titleGot, titleWant := updated.Title.Text, "Details"
+	if titleGot != titleWant {
+		t.Errorf("Unexpected step, got: %v, want: %v", titleGot, titleWant)
+	}
+}
+
+func TestWizardBackFromPushedStep(t *testing.T) {
+	t.Parallel()
+
+	_, tester, server, view := openTicketWizard(t, ticket{Urgent: true})
+	defer server.Close()
+
+	res := tester.ViewSubmission(slaptest.ViewSubmission{View: view})
+	var action struct {
+		View slack.View `json:"view"`
+	}
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+
+	res = tester.ViewSubmission(slaptest.ViewSubmission{View: action.View})
+	statusGot, statusWant := res.StatusCode, 200
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+	if body := res.Text(); body != "" {
+		t.Errorf("Expected the pushed view to be closed, got: %v", body)
+	}
+}
+
+// A MetadataStore that reports how many values it holds
+type countingMetadataStore struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func (s *countingMetadataStore) Save(key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = data
+	return nil
+}
+
+func (s *countingMetadataStore) Load(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.entries[key]
+	if !ok {
+		return nil, slap.ErrMetadataNotFound
+	}
+	return data, nil
+}
+
+func (s *countingMetadataStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *countingMetadataStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+func TestWizardFinishDeletesPushedMetadata(t *testing.T) {
+	t.Parallel()
+
+	server := slaptest.NewServer()
+	defer server.Close()
+	router := http.NewServeMux()
+	store := &countingMetadataStore{entries: make(map[string][]byte)}
+	app := slap.New(slap.Config{
+		Router:        router,
+		SigningSecret: "signing-secret",
+		BotToken: func(teamID string) (string, error) {
+			return "test", nil
+		},
+		APIURL:        server.APIURL(),
+		MetadataStore: store,
+	})
+	tester := slaptest.New(router, "signing-secret")
+
+	render := func(req slap.Request, state wizardState) (slack.ModalViewRequest, error) {
+		return slack.ModalViewRequest{Title: slack.NewTextBlockObject(slack.PlainTextType, "Notes", false, false)}, nil
+	}
+	wizard := slap.NewWizard[wizardState]("notes-wizard")
+	wizard.Step(slap.WizardStep[wizardState]{Name: "notes", Render: render})
+	wizard.Step(slap.WizardStep[wizardState]{
+		Name:   "confirm",
+		Render: render,
+		Submit: func(req *slap.ViewSubmissionRequest, state *wizardState) (string, error) {
+			return slap.WizardFinish, nil
+		},
+		Push: true,
+	})
+	slap.RegisterWizard(app, wizard)
+	app.RegisterCommand("/notes", func(req *slap.CommandRequest) error {
+		req.Ack()
+		return wizard.Open(req, wizardState{Notes: largeNotes()})
+	})
+	tester.Command(slaptest.Command{Command: "/notes"})
+
+	call, err := server.WaitForCall("views.open", time.Second)
+	if err != nil {
+		t.Fatalf("Expected a modal to be opened: %v", err.Error())
+	}
+	var view slack.View
+	if err := json.Unmarshal([]byte(call.Params.Get("view")), &view); err != nil {
+		t.Fatalf("Could not decode view: %v", err.Error())
+	}
+
+	res := tester.ViewSubmission(slaptest.ViewSubmission{View: view})
+	var action struct {
+		View slack.View `json:"view"`
+	}
+	if err := res.JSON(&action); err != nil {
+		t.Fatalf("Could not decode response: %v", err.Error())
+	}
+	if countGot, countWant := store.len(), 2; countGot != countWant {
+		t.Errorf("Unexpected number of stored values, got: %v, want: %v", countGot, countWant)
+	}
+
+	tester.ViewSubmission(slaptest.ViewSubmission{View: action.View})
+	if countGot, countWant := store.len(), 0; countGot != countWant {
+		t.Errorf("Expected every view's metadata to be deleted, got: %v stored values", countGot)
+	}
+}
+
+func TestWizardClosed(t *testing.T) {
+	t.Parallel()
+
+	app, tester, server := createConversationTestApp()
+	defer server.Close()
+
+	closed := make(chan ticket, 1)
+	wizard := createTicketWizard()
+	wizard.OnClose(func(req *slap.ViewClosedRequest, state ticket) error {
+		closed <- state
+		return nil
+	})
+	slap.RegisterWizard(app, wizard)
+	app.RegisterCommand("/ticket", func(req *slap.CommandRequest) error {
+		req.Ack()
+		return wizard.Open(req, ticket{Title: "Draft"})
+	})
+	tester.Command(slaptest.Command{Command: "/ticket"})
+
+	call, err := server.WaitForCall("views.open", time.Second)
+	if err != nil {
+		t.Fatalf("Expected a modal to be opened: %v", err.Error())
+	}
+	var view slack.View
+	if err := json.Unmarshal([]byte(call.Params.Get("view")), &view); err != nil {
+		t.Fatalf("Could not decode view: %v", err.Error())
+	}
+
+	res := tester.ViewClosed(slaptest.ViewClosed{View: view, IsCleared: true})
+	statusGot, statusWant := res.StatusCode, 200
+	if statusGot != statusWant {
+		t.Errorf("Unexpected status code, got: %v, want: %v", statusGot, statusWant)
+	}
+
+	select {
+	case state := <-closed:
+		titleGot, titleWant := state.Title, "Draft"
+		if titleGot != titleWant {
+			t.Errorf("Unexpected state, got: %v, want: %v", titleGot, titleWant)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected OnClose to be called")
+	}
+}